@@ -2,17 +2,21 @@
 package cli
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime/debug"
+	"slices"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/afero"
 
 	"github.com/mgechev/revive/config"
+	"github.com/mgechev/revive/lint"
 	"github.com/mgechev/revive/revivelib"
 )
 
@@ -52,17 +56,31 @@ func RunRevive(extraRules ...revivelib.ExtraRule) {
 	if err != nil {
 		fail(err.Error())
 	}
+	applyBuildFlags(conf)
 
-	revive, err := revivelib.New(
+	options := []revivelib.Option{
+		revivelib.WithSortedOutput(sortOutput),
+		revivelib.WithStableOutput(stableOutput),
+	}
+	for _, extraRule := range extraRules {
+		options = append(options, extraRule)
+	}
+	revive, err := revivelib.NewWithOptions(
 		conf,
 		setExitStatus,
 		maxOpenFiles,
-		extraRules...,
+		options...,
 	)
 	if err != nil {
 		fail(err.Error())
 	}
 
+	var profile *lint.Profile
+	if profileRules || profileRulesOutput != "" {
+		profile = lint.NewProfile()
+		revive.SetProfile(profile)
+	}
+
 	files := flag.Args()
 	packages := []*revivelib.LintPattern{}
 
@@ -79,27 +97,208 @@ func RunRevive(extraRules ...revivelib.ExtraRule) {
 		fail(err.Error())
 	}
 
-	output, exitCode, err := revive.Format(formatterName, failures)
+	arguments, err := formatterArguments()
+	if err != nil {
+		fail(err.Error())
+	}
+
+	outputs, outputFiles, err := formatterOutputs(formatterNames, arguments)
 	if err != nil {
 		fail(err.Error())
 	}
 
-	if output != "" {
-		fmt.Println(output)
+	failures, crashes := collectRuleCrashes(failures)
+	exitCode, err := revive.FormatToOutputs(outputs, failures)
+	for _, f := range outputFiles {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fail(err.Error())
+	}
+
+	if profile != nil {
+		if err := writeProfile(profile.Report(), profileRulesOutput); err != nil {
+			fail(err.Error())
+		}
+	}
+
+	if crashed := crashes(); len(crashed) > 0 {
+		fmt.Fprint(os.Stderr, ruleCrashesSummary(crashed))
+		exitCode = ruleCrashExitCode
 	}
 
 	os.Exit(exitCode) //revive:disable-line:deep-exit
 }
 
+// applyBuildFlags overrides the build constraints of the configuration with those set through command line flags.
+func applyBuildFlags(conf *lint.Config) {
+	if buildTags != "" {
+		conf.BuildTags = splitList(buildTags)
+	}
+	if goos != "" {
+		conf.GOOS = splitList(goos)
+	}
+	if goarch != "" {
+		conf.GOARCH = splitList(goarch)
+	}
+}
+
+// splitList splits a comma-separated list, ignoring empty elements.
+func splitList(list string) []string {
+	var result []string
+	for elem := range strings.SplitSeq(list, ",") {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			result = append(result, elem)
+		}
+	}
+	return result
+}
+
+// writeProfile writes the profile report as JSON to the file at path,
+// or as a table to the standard error if path is empty.
+func writeProfile(report lint.ProfileReport, path string) error {
+	if path == "" {
+		return report.WriteTable(os.Stderr)
+	}
+
+	f, err := os.Create(path) //nolint:gosec // ignore G304: potential file inclusion via variable
+	if err != nil {
+		return fmt.Errorf("writing rules profile: %w", err)
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		f.Close()
+		return fmt.Errorf("writing rules profile: %w", err)
+	}
+	return f.Close()
+}
+
+// ruleCrashExitCode is the exit code used when at least one rule panicked.
+const ruleCrashExitCode = 3
+
+// collectRuleCrashes forwards the failures, except the rule crashes which it collects.
+// The returned function yields the collected rule crashes; it must only be called
+// once the forwarded channel has been drained.
+func collectRuleCrashes(failures <-chan lint.Failure) (<-chan lint.Failure, func() []lint.Failure) {
+	var crashes []lint.Failure
+	forwarded := make(chan lint.Failure)
+	go func() {
+		defer close(forwarded)
+		for failure := range failures {
+			if failure.IsRuleCrash() {
+				crashes = append(crashes, failure)
+				continue
+			}
+			forwarded <- failure
+		}
+	}()
+
+	return forwarded, func() []lint.Failure { return crashes }
+}
+
+// ruleCrashesSummary returns a summary of the rules that panicked, with the files they panicked on.
+func ruleCrashesSummary(crashes []lint.Failure) string {
+	filesByRule := map[string][]string{}
+	for _, crash := range crashes {
+		filesByRule[crash.RuleName] = append(filesByRule[crash.RuleName], crash.Filename())
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d rule(s) crashed, their failures are missing from the report:\n", len(filesByRule))
+	for _, rule := range slices.Sorted(maps.Keys(filesByRule)) {
+		files := filesByRule[rule]
+		slices.Sort(files)
+		fmt.Fprintf(&sb, "  %s on %s\n", rule, strings.Join(files, ", "))
+	}
+	sb.WriteString("Run again with REVIVE_LOG_LEVEL=debug to get the stack traces, and please report it at https://github.com/mgechev/revive/issues\n")
+	return sb.String()
+}
+
+// formatterOutputs builds the formatter outputs from the values of the -formatter flag.
+// Each value has the form NAME[:PATH]; without a path, the output goes to the standard output.
+// It also returns the files it created, which the caller must close.
+func formatterOutputs(values []string, arguments lint.FormatterArguments) ([]revivelib.FormatterOutput, []*os.File, error) {
+	if len(values) == 0 {
+		values = []string{""}
+	}
+
+	var outputs []revivelib.FormatterOutput
+	var files []*os.File
+	for _, value := range values {
+		name, path := splitFormatterFlag(value)
+		output := revivelib.FormatterOutput{
+			Name:      name,
+			Arguments: arguments,
+			Writer:    os.Stdout,
+		}
+		if path != "" {
+			f, err := os.Create(path) //nolint:gosec // ignore G304: potential file inclusion via variable
+			if err != nil {
+				for _, f := range files {
+					_ = f.Close()
+				}
+				return nil, nil, fmt.Errorf("creating output file of formatter %q: %w", name, err)
+			}
+			files = append(files, f)
+			output.Writer = f
+		}
+		outputs = append(outputs, output)
+	}
+
+	return outputs, files, nil
+}
+
+// splitFormatterFlag splits a -formatter value of the form NAME[:PATH] into the formatter name and the output path.
+func splitFormatterFlag(value string) (name, path string) {
+	name, path, _ = strings.Cut(value, ":")
+	return name, path
+}
+
 var (
-	configPath      string
-	excludePatterns revivelib.ArrayFlags
-	formatterName   string
-	versionFlag     bool
-	setExitStatus   bool
-	maxOpenFiles    int
+	configPath         string
+	excludePatterns    revivelib.ArrayFlags
+	formatterNames     revivelib.ArrayFlags
+	formatterTemplate  string
+	formatterArgs      revivelib.ArrayFlags
+	comparePath        string
+	showSource         bool
+	sortOutput         bool
+	stableOutput       bool
+	profileRules       bool
+	profileRulesOutput string
+	buildTags          string
+	goos               string
+	goarch             string
+	versionFlag        bool
+	setExitStatus      bool
+	maxOpenFiles       int
 )
 
+// formatterArguments returns the formatter arguments set through command line flags.
+func formatterArguments() (lint.FormatterArguments, error) {
+	args := lint.FormatterArguments{}
+	for _, arg := range formatterArgs {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid formatter argument %q, expected key=value", arg)
+		}
+		args[key] = value
+	}
+	if formatterTemplate != "" {
+		args["template"] = formatterTemplate
+	}
+	if comparePath != "" {
+		args["compare"] = comparePath
+	}
+	if showSource {
+		args["show-source"] = "true"
+	}
+	return args, nil
+}
+
 var originalUsage = flag.Usage
 
 func logo() string {
@@ -156,19 +355,41 @@ func initConfig() {
 
 	// command line help strings
 	const (
-		configUsage       = "path to the configuration TOML file, defaults to $XDG_CONFIG_HOME/revive.toml or $HOME/revive.toml, if present (i.e. -config myconf.toml)"
-		excludeUsage      = "list of globs which specify files to be excluded (i.e. -exclude foo/...)"
-		formatterUsage    = "formatter to be used for the output (i.e. -formatter stylish)"
-		versionUsage      = "get revive version"
-		exitStatusUsage   = "set exit status to 1 if any issues are found, overwrites error-code and warning-code in config"
-		maxOpenFilesUsage = "maximum number of open files at the same time"
+		configUsage        = "path to the configuration TOML file, defaults to $XDG_CONFIG_HOME/revive.toml or $HOME/revive.toml, if present (i.e. -config myconf.toml)"
+		excludeUsage       = "list of globs which specify files to be excluded (i.e. -exclude foo/...)"
+		formatterUsage     = "formatter to be used for the output, optionally followed by the file to write it to; can be repeated (i.e. -formatter stylish -formatter sarif:revive.sarif)"
+		templateUsage      = "template file or inline template for the template formatter (i.e. -formatter template -formatter-template report.tmpl)"
+		formatterArgUsage  = "argument passed to the formatters, in the form key=value; can be repeated (i.e. -formatter stats -formatter-arg top=5)"
+		showSourceUsage    = "print the source code of each failure, for the friendly and stylish formatters"
+		sortUsage          = "sort the failures by file, line, column and rule, except for formatters streaming their output (i.e. default, plain, unix)"
+		stableUsage        = "sort the failures for all the formatters, which then write their output once the lint is complete"
+		compareUsage       = "output of the json formatter from a previous run to compare with, for the stats formatter (i.e. -formatter stats -compare previous.json)"
+		profileRulesUsage  = "print the time spent by each rule, and parsing and type checking each package, to the standard error"
+		profileOutputUsage = "write the profile of the rules as JSON to the given file instead (i.e. -profile-rules-output profile.json)"
+		tagsUsage          = "comma-separated list of build tags; only the files satisfying the build constraints are linted (i.e. -tags integration,e2e)"
+		goosUsage          = "comma-separated list of target operating systems; each combination with the target architectures is linted (i.e. -goos linux,windows)"
+		goarchUsage        = "comma-separated list of target architectures (i.e. -goarch amd64,arm64)"
+		versionUsage       = "get revive version"
+		exitStatusUsage    = "set exit status to 1 if any issues are found, overwrites error-code and warning-code in config"
+		maxOpenFilesUsage  = "maximum number of open files at the same time"
 	)
 
 	defaultConfigPath := buildDefaultConfigPath()
 
 	flag.StringVar(&configPath, "config", defaultConfigPath, configUsage)
 	flag.Var(&excludePatterns, "exclude", excludeUsage)
-	flag.StringVar(&formatterName, "formatter", "", formatterUsage)
+	flag.Var(&formatterNames, "formatter", formatterUsage)
+	flag.StringVar(&formatterTemplate, "formatter-template", "", templateUsage)
+	flag.Var(&formatterArgs, "formatter-arg", formatterArgUsage)
+	flag.StringVar(&comparePath, "compare", "", compareUsage)
+	flag.BoolVar(&showSource, "show-source", false, showSourceUsage)
+	flag.BoolVar(&sortOutput, "sort", true, sortUsage)
+	flag.BoolVar(&stableOutput, "stable", false, stableUsage)
+	flag.BoolVar(&profileRules, "profile-rules", false, profileRulesUsage)
+	flag.StringVar(&profileRulesOutput, "profile-rules-output", "", profileOutputUsage)
+	flag.StringVar(&buildTags, "tags", "", tagsUsage)
+	flag.StringVar(&goos, "goos", "", goosUsage)
+	flag.StringVar(&goarch, "goarch", "", goarchUsage)
 	flag.BoolVar(&versionFlag, "version", false, versionUsage)
 	flag.BoolVar(&setExitStatus, "set_exit_status", false, exitStatusUsage)
 	flag.IntVar(&maxOpenFiles, "max_open_files", 0, maxOpenFilesUsage)
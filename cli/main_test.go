@@ -4,9 +4,12 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"testing"
 
 	"github.com/spf13/afero"
+
+	"github.com/mgechev/revive/lint"
 )
 
 func TestMain(m *testing.M) {
@@ -133,3 +136,65 @@ func TestGetDevelopmentVersion(t *testing.T) {
 		t.Errorf("getVersion() = %q, want %q", got, want)
 	}
 }
+
+func TestSplitFormatterFlag(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantName string
+		wantPath string
+	}{
+		{"", "", ""},
+		{"friendly", "friendly", ""},
+		{"sarif:revive.sarif", "sarif", "revive.sarif"},
+		{"json:C:\\reports\\revive.json", "json", "C:\\reports\\revive.json"},
+	}
+	for _, tt := range tests {
+		name, path := splitFormatterFlag(tt.value)
+		if name != tt.wantName || path != tt.wantPath {
+			t.Errorf("splitFormatterFlag(%q) = (%q, %q), want (%q, %q)", tt.value, name, path, tt.wantName, tt.wantPath)
+		}
+	}
+}
+
+func TestCollectRuleCrashes(t *testing.T) {
+	failures := make(chan lint.Failure, 3)
+	failures <- lint.NewRuleCrashFailure("rule-b", "b.go", "boom")
+	failures <- lint.Failure{RuleName: "rule-c", Failure: "regular failure"}
+	failures <- lint.NewRuleCrashFailure("rule-a", "a.go", "boom")
+	close(failures)
+
+	forwarded, crashes := collectRuleCrashes(failures)
+	var got []lint.Failure
+	for failure := range forwarded {
+		got = append(got, failure)
+	}
+
+	if len(got) != 1 || got[0].RuleName != "rule-c" {
+		t.Errorf("expected only the failure of rule-c to be forwarded, got %+v", got)
+	}
+
+	want := `2 rule(s) crashed, their failures are missing from the report:
+  rule-a on a.go
+  rule-b on b.go
+Run again with REVIVE_LOG_LEVEL=debug to get the stack traces, and please report it at https://github.com/mgechev/revive/issues
+`
+	if summary := ruleCrashesSummary(crashes()); summary != want {
+		t.Errorf("got summary %q, want %q", summary, want)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		list string
+		want []string
+	}{
+		{"", nil},
+		{"linux", []string{"linux"}},
+		{"linux, windows,,darwin", []string{"linux", "windows", "darwin"}},
+	}
+	for _, tt := range tests {
+		if got := splitList(tt.list); !slices.Equal(got, tt.want) {
+			t.Errorf("splitList(%q) = %q, want %q", tt.list, got, tt.want)
+		}
+	}
+}
@@ -4,8 +4,11 @@ package config
 import (
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -16,118 +19,132 @@ import (
 	"github.com/mgechev/revive/rule"
 )
 
-var defaultRules = []lint.Rule{
-	&rule.VarDeclarationsRule{},
-	&rule.PackageCommentsRule{},
-	&rule.DotImportsRule{},
-	&rule.BlankImportsRule{},
-	&rule.ExportedRule{},
-	&rule.VarNamingRule{},
-	&rule.IndentErrorFlowRule{},
-	&rule.RangeRule{},
-	&rule.ErrorfRule{},
-	&rule.ErrorNamingRule{},
-	&rule.ErrorStringsRule{},
-	&rule.ReceiverNamingRule{},
-	&rule.IncrementDecrementRule{},
-	&rule.ErrorReturnRule{},
-	&rule.UnexportedReturnRule{},
-	&rule.TimeNamingRule{},
-	&rule.ContextKeysType{},
-	&rule.ContextAsArgumentRule{},
-	&rule.EmptyBlockRule{},
-	&rule.SuperfluousElseRule{},
-	&rule.UnusedParamRule{},
-	&rule.UnreachableCodeRule{},
-	&rule.RedefinesBuiltinIDRule{},
+// defaultRules is the list of the constructors of the built-in rules enabled when no configuration is provided.
+var defaultRules = []func() lint.Rule{
+	newRule[rule.VarDeclarationsRule],
+	newRule[rule.PackageCommentsRule],
+	newRule[rule.DotImportsRule],
+	newRule[rule.BlankImportsRule],
+	newRule[rule.ExportedRule],
+	newRule[rule.VarNamingRule],
+	newRule[rule.IndentErrorFlowRule],
+	newRule[rule.RangeRule],
+	newRule[rule.ErrorfRule],
+	newRule[rule.ErrorNamingRule],
+	newRule[rule.ErrorStringsRule],
+	newRule[rule.ReceiverNamingRule],
+	newRule[rule.IncrementDecrementRule],
+	newRule[rule.ErrorReturnRule],
+	newRule[rule.UnexportedReturnRule],
+	newRule[rule.TimeNamingRule],
+	newRule[rule.ContextKeysType],
+	newRule[rule.ContextAsArgumentRule],
+	newRule[rule.EmptyBlockRule],
+	newRule[rule.SuperfluousElseRule],
+	newRule[rule.UnusedParamRule],
+	newRule[rule.UnreachableCodeRule],
+	newRule[rule.RedefinesBuiltinIDRule],
 }
 
-var allRules = append([]lint.Rule{
-	&rule.ArgumentsLimitRule{},
-	&rule.CyclomaticRule{},
-	&rule.FileHeaderRule{},
-	&rule.ConfusingNamingRule{},
-	&rule.GetReturnRule{},
-	&rule.ModifiesParamRule{},
-	&rule.ConfusingResultsRule{},
-	&rule.DeepExitRule{},
-	&rule.AddConstantRule{},
-	&rule.FlagParamRule{},
-	&rule.UnnecessaryStmtRule{},
-	&rule.StructTagRule{},
-	&rule.ModifiesValRecRule{},
-	&rule.ConstantLogicalExprRule{},
-	&rule.BoolLiteralRule{},
-	&rule.ImportsBlocklistRule{},
-	&rule.FunctionResultsLimitRule{},
-	&rule.MaxPublicStructsRule{},
-	&rule.RangeValInClosureRule{},
-	&rule.RangeValAddress{},
-	&rule.WaitGroupByValueRule{},
-	&rule.AtomicRule{},
-	&rule.EmptyLinesRule{},
-	&rule.LineLengthLimitRule{},
-	&rule.CallToGCRule{},
-	&rule.DuplicatedImportsRule{},
-	&rule.ImportShadowingRule{},
-	&rule.BareReturnRule{},
-	&rule.UnusedReceiverRule{},
-	&rule.UnhandledErrorRule{},
-	&rule.CognitiveComplexityRule{},
-	&rule.StringOfIntRule{},
-	&rule.StringFormatRule{},
-	&rule.EarlyReturnRule{},
-	&rule.UnconditionalRecursionRule{},
-	&rule.IdenticalBranchesRule{},
-	&rule.DeferRule{},
-	&rule.UnexportedNamingRule{},
-	&rule.FunctionLength{},
-	&rule.NestedStructs{},
-	&rule.UselessBreak{},
-	&rule.UncheckedTypeAssertionRule{},
-	&rule.TimeEqualRule{},
-	&rule.TimeDateRule{},
-	&rule.BannedCharsRule{},
-	&rule.OptimizeOperandsOrderRule{},
-	&rule.UseAnyRule{},
-	&rule.DataRaceRule{},
-	&rule.CommentSpacingsRule{},
-	&rule.IfReturnRule{},
-	&rule.RedundantImportAlias{},
-	&rule.RedundantCanonicalImport{},
-	&rule.ImportAliasNamingRule{},
-	&rule.EnforceMapStyleRule{},
-	&rule.EnforceRepeatedArgTypeStyleRule{},
-	&rule.EnforceSliceStyleRule{},
-	&rule.MaxControlNestingRule{},
-	&rule.CommentsDensityRule{},
-	&rule.FileLengthLimitRule{},
-	&rule.FilenameFormatRule{},
-	&rule.RedundantBuildTagRule{},
-	&rule.UseErrorsNewRule{},
-	&rule.RedundantTestMainExitRule{},
-	&rule.UnnecessaryFormatRule{},
-	&rule.UseFmtPrintRule{},
-	&rule.EnforceSwitchStyleRule{},
-	&rule.IdenticalSwitchConditionsRule{},
-	&rule.IdenticalIfElseIfConditionsRule{},
-	&rule.IdenticalIfElseIfBranchesRule{},
-	&rule.IdenticalSwitchBranchesRule{},
-	&rule.UselessFallthroughRule{},
-	&rule.PackageDirectoryMismatchRule{},
-	&rule.UseWaitGroupGoRule{},
-	&rule.UnsecureURLSchemeRule{},
-	&rule.InefficientMapLookupRule{},
-	&rule.ForbiddenCallInWgGoRule{},
-	&rule.UnnecessaryIfRule{},
-	&rule.EpochNamingRule{},
-	&rule.UseSlicesSort{},
-	&rule.PackageNamingRule{},
-	&rule.MultilineIfInitRule{},
-	&rule.MarshalReceiverRule{},
+// allRules is the list of the constructors of all built-in rules.
+var allRules = append([]func() lint.Rule{
+	newRule[rule.ArgumentsLimitRule],
+	newRule[rule.CyclomaticRule],
+	newRule[rule.FileHeaderRule],
+	newRule[rule.ConfusingNamingRule],
+	newRule[rule.GetReturnRule],
+	newRule[rule.ModifiesParamRule],
+	newRule[rule.ConfusingResultsRule],
+	newRule[rule.DeepExitRule],
+	newRule[rule.AddConstantRule],
+	newRule[rule.FlagParamRule],
+	newRule[rule.UnnecessaryStmtRule],
+	newRule[rule.StructTagRule],
+	newRule[rule.ModifiesValRecRule],
+	newRule[rule.ConstantLogicalExprRule],
+	newRule[rule.BoolLiteralRule],
+	newRule[rule.ImportsBlocklistRule],
+	newRule[rule.FunctionResultsLimitRule],
+	newRule[rule.MaxPublicStructsRule],
+	newRule[rule.RangeValInClosureRule],
+	newRule[rule.RangeValAddress],
+	newRule[rule.WaitGroupByValueRule],
+	newRule[rule.AtomicRule],
+	newRule[rule.EmptyLinesRule],
+	newRule[rule.LineLengthLimitRule],
+	newRule[rule.CallToGCRule],
+	newRule[rule.DuplicatedImportsRule],
+	newRule[rule.ImportShadowingRule],
+	newRule[rule.BareReturnRule],
+	newRule[rule.UnusedReceiverRule],
+	newRule[rule.UnhandledErrorRule],
+	newRule[rule.CognitiveComplexityRule],
+	newRule[rule.StringOfIntRule],
+	newRule[rule.StringFormatRule],
+	newRule[rule.EarlyReturnRule],
+	newRule[rule.UnconditionalRecursionRule],
+	newRule[rule.IdenticalBranchesRule],
+	newRule[rule.DeferRule],
+	newRule[rule.UnexportedNamingRule],
+	newRule[rule.FunctionLength],
+	newRule[rule.NestedStructs],
+	newRule[rule.UselessBreak],
+	newRule[rule.UncheckedTypeAssertionRule],
+	newRule[rule.TimeEqualRule],
+	newRule[rule.TimeDateRule],
+	newRule[rule.BannedCharsRule],
+	newRule[rule.OptimizeOperandsOrderRule],
+	newRule[rule.UseAnyRule],
+	newRule[rule.DataRaceRule],
+	newRule[rule.CommentSpacingsRule],
+	newRule[rule.IfReturnRule],
+	newRule[rule.RedundantImportAlias],
+	newRule[rule.RedundantCanonicalImport],
+	newRule[rule.ImportAliasNamingRule],
+	newRule[rule.EnforceMapStyleRule],
+	newRule[rule.EnforceRepeatedArgTypeStyleRule],
+	newRule[rule.EnforceSliceStyleRule],
+	newRule[rule.MaxControlNestingRule],
+	newRule[rule.CommentsDensityRule],
+	newRule[rule.FileLengthLimitRule],
+	newRule[rule.FilenameFormatRule],
+	newRule[rule.RedundantBuildTagRule],
+	newRule[rule.UseErrorsNewRule],
+	newRule[rule.RedundantTestMainExitRule],
+	newRule[rule.UnnecessaryFormatRule],
+	newRule[rule.UseFmtPrintRule],
+	newRule[rule.EnforceSwitchStyleRule],
+	newRule[rule.IdenticalSwitchConditionsRule],
+	newRule[rule.IdenticalIfElseIfConditionsRule],
+	newRule[rule.IdenticalIfElseIfBranchesRule],
+	newRule[rule.IdenticalSwitchBranchesRule],
+	newRule[rule.UselessFallthroughRule],
+	newRule[rule.PackageDirectoryMismatchRule],
+	newRule[rule.UseWaitGroupGoRule],
+	newRule[rule.UnsecureURLSchemeRule],
+	newRule[rule.InefficientMapLookupRule],
+	newRule[rule.ForbiddenCallInWgGoRule],
+	newRule[rule.UnnecessaryIfRule],
+	newRule[rule.EpochNamingRule],
+	newRule[rule.UseSlicesSort],
+	newRule[rule.PackageNamingRule],
+	newRule[rule.MultilineIfInitRule],
+	newRule[rule.MarshalReceiverRule],
+	newRule[rule.ErrorWrappingRule],
+	newRule[rule.ContextPropagationRule],
+	newRule[rule.UnclosedResourceRule],
+	newRule[rule.MutexUsageRule],
 }, defaultRules...)
 
-// allFormatters is a list of all available formatters to output the linting results.
+// newRule is the constructor of the built-in rule of type T.
+func newRule[T any, PT interface {
+	*T
+	lint.Rule
+}]() lint.Rule {
+	return PT(new(T))
+}
+
+// allFormatters is a list of all built-in formatters to output the linting results.
 // Keep the list sorted and in sync with available formatters in README.md.
 var allFormatters = []lint.Formatter{
 	&formatter.Checkstyle{},
@@ -136,36 +153,51 @@ var allFormatters = []lint.Formatter{
 	&formatter.JSON{},
 	&formatter.NDJSON{},
 	&formatter.Plain{},
+	&formatter.RDJSON{},
+	&formatter.RDJSONL{},
 	&formatter.Sarif{},
+	&formatter.Stats{},
 	&formatter.Stylish{},
+	&formatter.Template{},
 	&formatter.Unix{},
 }
 
-func getFormatters() map[string]lint.Formatter {
-	result := map[string]lint.Formatter{}
-	for _, f := range allFormatters {
-		result[f.Name()] = f
-	}
-	return result
-}
-
 // GetLintingRules yields the linting rules that must be applied by the linter.
+//
+// The rules are looked up in the registry, then among the extra rules and the rules defined
+// in the configuration (external and pattern rules). The rules registered with a constructor,
+// such as the built-in ones, are created anew before being configured, so that lint sessions
+// with different configurations do not affect each other.
+// An extra rule must not have the name of another extra rule, nor of a registered rule
+// unless it is of the same type (i.e. it only ensures the registered rule is available).
 func GetLintingRules(config *lint.Config, extraRules []lint.Rule) ([]lint.Rule, error) {
-	rulesMap := map[string]lint.Rule{}
-	for _, r := range allRules {
-		rulesMap[r.Name()] = r
+	configuredRules, err := getConfiguredRules(config)
+	if err != nil {
+		return nil, err
 	}
+	extraRules = append(slices.Clip(extraRules), configuredRules...)
+
+	extraRulesMap := map[string]lint.Rule{}
 	for _, r := range extraRules {
-		if _, ok := rulesMap[r.Name()]; ok {
-			continue
+		name := r.Name()
+		if registered, ok := LookupRule(name); ok {
+			if reflect.TypeOf(registered) == reflect.TypeOf(r) {
+				continue
+			}
+			return nil, fmt.Errorf("duplicate rule name %q, already a registered rule", name)
 		}
-		rulesMap[r.Name()] = r
+		if _, ok := extraRulesMap[name]; ok {
+			return nil, fmt.Errorf("duplicate rule name %q", name)
+		}
+		extraRulesMap[name] = r
 	}
 
 	var lintingRules []lint.Rule
 	for name, ruleConfig := range config.Rules {
-		actualName := actualRuleName(name)
-		r, ok := rulesMap[actualName]
+		r, ok := newRegisteredRule(name)
+		if !ok {
+			r, ok = extraRulesMap[name]
+		}
 		if !ok {
 			return nil, fmt.Errorf("cannot find rule: %s", name)
 		}
@@ -186,13 +218,25 @@ func GetLintingRules(config *lint.Config, extraRules []lint.Rule) ([]lint.Rule,
 	return lintingRules, nil
 }
 
-func actualRuleName(name string) string {
-	switch name {
-	case "imports-blacklist":
-		return "imports-blocklist"
-	default:
-		return name
+// getConfiguredRules yields the rules defined in the configuration: first the rules implemented
+// by external commands, then the rules matching syntax patterns, each sorted by name.
+func getConfiguredRules(config *lint.Config) ([]lint.Rule, error) {
+	var result []lint.Rule
+	for _, name := range slices.Sorted(maps.Keys(config.External)) {
+		r, err := rule.NewExternalRule(name, config.External[name].Command)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	for _, name := range slices.Sorted(maps.Keys(config.Patterns)) {
+		r, err := rule.NewPatternRule(name, config.Patterns[name])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
 	}
+	return result, nil
 }
 
 func parseConfig(data []byte, config *lint.Config) error {
@@ -253,6 +297,13 @@ func validateConfig(config *lint.Config) error {
 	if config.EnableAllRules && config.EnableDefaultRules {
 		return errors.New("config options enable-all-rules and enable-default-rules cannot be combined")
 	}
+	for ruleName, overlapped := range config.OverlappingRules {
+		for _, other := range overlapped {
+			if other == ruleName || slices.Contains(config.OverlappingRules[other], ruleName) {
+				return fmt.Errorf("config option overlapping-rules: the rules %s and %s cannot overlap each other", ruleName, other)
+			}
+		}
+	}
 	return nil
 }
 
@@ -270,10 +321,23 @@ func normalizeConfig(config *lint.Config) {
 		}
 	}
 
+	for name := range config.External {
+		if _, ok := config.Rules[name]; !ok {
+			config.Rules[name] = lint.RuleConfig{}
+		}
+	}
+	for name, pattern := range config.Patterns {
+		ruleConfig := config.Rules[name]
+		if ruleConfig.Severity == "" {
+			ruleConfig.Severity = pattern.Severity
+		}
+		config.Rules[name] = ruleConfig
+	}
+
 	if config.EnableAllRules {
-		addRules(config, allRules)
+		addRules(config, Rules())
 	} else if config.EnableDefaultRules {
-		addRules(config, defaultRules)
+		addRules(config, DefaultRules())
 	}
 
 	severity := config.Severity
@@ -297,23 +361,36 @@ const defaultConfidence = 0.8
 
 // GetConfig yields the configuration.
 func GetConfig(configPath string) (*lint.Config, error) {
-	config := &lint.Config{}
-	switch {
-	case configPath != "":
-		config.Confidence = defaultConfidence
-		data, err := os.ReadFile(configPath) //nolint:gosec // ignore G304: potential file inclusion via variable
-		if err != nil {
-			return nil, errors.New("cannot read the config file")
-		}
-		err = parseConfig(data, config)
-		if err != nil {
-			return nil, err
-		}
+	if configPath == "" { // no configuration provided
+		return loadConfig(defaultConfig())
+	}
 
-	default: // no configuration provided
-		config = defaultConfig()
+	data, err := os.ReadFile(configPath) //nolint:gosec // ignore G304: potential file inclusion via variable
+	if err != nil {
+		return nil, errors.New("cannot read the config file")
 	}
+	return parseAndLoadConfig(data)
+}
 
+// ReadConfig yields the configuration read from r, in the TOML format of the configuration file.
+func ReadConfig(r io.Reader) (*lint.Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the config: %w", err)
+	}
+	return parseAndLoadConfig(data)
+}
+
+func parseAndLoadConfig(data []byte) (*lint.Config, error) {
+	config := &lint.Config{Confidence: defaultConfidence}
+	if err := parseConfig(data, config); err != nil {
+		return nil, err
+	}
+	return loadConfig(config)
+}
+
+// loadConfig validates and normalizes the configuration.
+func loadConfig(config *lint.Config) (*lint.Config, error) {
 	if err := validateConfig(config); err != nil {
 		return nil, err
 	}
@@ -324,24 +401,54 @@ func GetConfig(configPath string) (*lint.Config, error) {
 
 // GetFormatter yields the formatter for lint failures.
 func GetFormatter(formatterName string) (lint.Formatter, error) {
-	formatters := getFormatters()
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
 	if formatterName == "" {
-		return formatters["default"], nil
+		formatterName = "default"
 	}
-	f, ok := formatters[formatterName]
+	f, ok := registeredFormatters[formatterName]
 	if !ok {
 		return nil, fmt.Errorf("unknown formatter %v", formatterName)
 	}
 	return f, nil
 }
 
+// NewFormatter yields a new instance of the named formatter, configured with the given arguments.
+//
+// Arguments are ignored by formatters that do not implement [lint.ConfigurableFormatter].
+func NewFormatter(formatterName string, arguments lint.FormatterArguments) (lint.Formatter, error) {
+	f, err := GetFormatter(formatterName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Formatters can hold configuration, so never hand out the shared instance.
+	typ := reflect.TypeOf(f)
+	if typ == nil || typ.Kind() != reflect.Pointer {
+		return nil, fmt.Errorf("cannot instantiate formatter %v", formatterName)
+	}
+	f, ok := reflect.New(typ.Elem()).Interface().(lint.Formatter)
+	if !ok {
+		return nil, fmt.Errorf("cannot instantiate formatter %v", formatterName)
+	}
+
+	if cf, ok := f.(lint.ConfigurableFormatter); ok {
+		if err := cf.Configure(arguments); err != nil {
+			return nil, fmt.Errorf("cannot configure formatter %q: %w", formatterName, err)
+		}
+	}
+
+	return f, nil
+}
+
 func defaultConfig() *lint.Config {
 	defaultConfig := lint.Config{
 		Confidence: defaultConfidence,
 		Severity:   lint.SeverityWarning,
 		Rules:      map[string]lint.RuleConfig{},
 	}
-	for _, r := range defaultRules {
+	for _, r := range DefaultRules() {
 		defaultConfig.Rules[r.Name()] = lint.RuleConfig{}
 	}
 	return &defaultConfig
@@ -429,6 +429,10 @@ func TestGetConfig(t *testing.T) {
 				confPath:  "duplicate-option.toml",
 				wantError: "refer to the same option",
 			},
+			"rules overlapping each other": {
+				confPath:  "overlapping-rules-mutual.toml",
+				wantError: "cannot overlap each other",
+			},
 		} {
 			t.Run(name, func(t *testing.T) {
 				_, err := config.GetConfig(filepath.Join("testdata", tc.confPath))
@@ -533,7 +537,7 @@ func TestGetLintingRules(t *testing.T) {
 		// len of defaultRules
 		defaultRulesCount = 23
 		// len of allRules: update this when adding new rules
-		allRulesCount = 105
+		allRulesCount = 109
 	)
 
 	tt := map[string]struct {
@@ -654,6 +658,36 @@ func TestGetLintingRules(t *testing.T) {
 				"imports-blacklist", // non-default deprecated rule name
 			},
 		},
+		"external rules": {
+			confPath:       "external.toml",
+			wantRulesCount: 3,
+			wantEnabledRules: []string{
+				"exported",    // default rule
+				"org-naming",  // external rule
+				"org-logging", // external rule with rule configuration
+			},
+		},
+		"external rule with the name of a registered rule": {
+			confPath: "external-duplicate.toml",
+			wantErr:  `duplicate rule name "exported", already a registered rule`,
+		},
+		"external rule without command": {
+			confPath: "external-no-command.toml",
+			wantErr:  "the external rule org-naming requires a command",
+		},
+		"pattern rules": {
+			confPath:       "pattern.toml",
+			wantRulesCount: 3,
+			wantEnabledRules: []string{
+				"exported",           // default rule
+				"sprintf-string",     // pattern rule
+				"errors-new-sprintf", // pattern rule
+			},
+		},
+		"pattern rule with unknown variable": {
+			confPath: "pattern-invalid.toml",
+			wantErr:  "the pattern rule sprintf-string: unknown variable $y, the pattern has [x]",
+		},
 		"var-naming configure error": {
 			confPath: "var-naming-configure-error.toml",
 			wantErr:  `cannot configure rule: "var-naming": invalid argument to the var-naming rule. Expecting a allowlist of type slice with initialisms, got string`,
@@ -751,6 +785,40 @@ func TestGetGlobalSeverity(t *testing.T) {
 	}
 }
 
+func TestGetConfig_patternSeverity(t *testing.T) {
+	cfg, err := config.GetConfig(filepath.Join("testdata", "pattern.toml"))
+	if err != nil {
+		t.Fatalf("Unexpected error while loading conf: %v", err)
+	}
+
+	for ruleName, want := range map[string]lint.Severity{
+		"sprintf-string":     lint.SeverityError,
+		"errors-new-sprintf": "",
+	} {
+		if got := cfg.Rules[ruleName].Severity; got != want {
+			t.Errorf("Expected Severity %q for rule %v, got %q", want, ruleName, got)
+		}
+	}
+}
+
+func TestReadConfig(t *testing.T) {
+	cfg, err := config.ReadConfig(strings.NewReader("severity = \"error\"\n\n[rule.var-naming]\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error while reading conf: %v", err)
+	}
+
+	if cfg.Confidence != 0.8 {
+		t.Errorf("Expected the default confidence 0.8, got %v", cfg.Confidence)
+	}
+	if got := cfg.Rules["var-naming"].Severity; got != lint.SeverityError {
+		t.Errorf("Expected Severity %q for rule var-naming, got %q", lint.SeverityError, got)
+	}
+
+	if _, err := config.ReadConfig(strings.NewReader("enable-all-rules = true\nenable-default-rules = true\n")); err == nil {
+		t.Error("Expected an error reading an invalid config")
+	}
+}
+
 func TestGetFormatter(t *testing.T) {
 	t.Run("default formatter", func(t *testing.T) {
 		formatter, err := config.GetFormatter("")
@@ -777,3 +845,31 @@ func TestGetFormatter(t *testing.T) {
 		}
 	})
 }
+
+func TestNewFormatter(t *testing.T) {
+	t.Run("fresh instance", func(t *testing.T) {
+		shared, err := config.GetFormatter("template")
+		if err != nil {
+			t.Fatalf("Unexpected error: %q", err)
+		}
+		formatter, err := config.NewFormatter("template", lint.FormatterArguments{"template": "{{ .Failure }}"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %q", err)
+		}
+		if formatter == shared {
+			t.Error("Expected a new formatter instance, got the shared one")
+		}
+	})
+	t.Run("unknown formatter", func(t *testing.T) {
+		_, err := config.NewFormatter("unknown", nil)
+		if err == nil || err.Error() != "unknown formatter unknown" {
+			t.Errorf("Expected error %q, got: %q", "unknown formatter unknown", err)
+		}
+	})
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := config.NewFormatter("template", lint.FormatterArguments{"template": "testdata/not-found.tmpl"})
+		if err == nil {
+			t.Error("Expected an error for a missing template file")
+		}
+	})
+}
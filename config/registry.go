@@ -0,0 +1,267 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/mgechev/revive/lint"
+)
+
+// The registry holds the rules and formatters known to revive, keyed by name.
+// It is seeded with the built-in rules and formatters, and can be modified
+// by custom binaries before loading the configuration.
+var (
+	registryMu sync.RWMutex
+
+	registeredRules map[string]registeredRule
+	// defaultRuleNames is the set of rules enabled when no configuration is provided.
+	defaultRuleNames map[string]bool
+	// ruleAliases maps alternative rule names to the names of registered rules.
+	ruleAliases map[string]string
+
+	registeredFormatters map[string]lint.Formatter
+)
+
+// registeredRule is a rule of the registry, with the constructor of the instance configured
+// by each lint session, if any.
+type registeredRule struct {
+	rule    lint.Rule
+	newRule func() lint.Rule // nil if the lint sessions share the registered instance
+}
+
+func init() {
+	registeredRules = map[string]registeredRule{}
+	defaultRuleNames = map[string]bool{}
+	ruleAliases = map[string]string{}
+	registeredFormatters = map[string]lint.Formatter{}
+
+	for _, newRule := range allRules {
+		mustRegister(RegisterRuleFunc(newRule))
+	}
+	for _, newRule := range defaultRules {
+		defaultRuleNames[newRule().Name()] = true
+	}
+	mustRegister(RegisterRuleAlias("imports-blacklist", "imports-blocklist"))
+
+	for _, f := range allFormatters {
+		mustRegister(RegisterFormatter(f))
+	}
+}
+
+func mustRegister(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// RegisterRule adds the rule to the registry, making it available in the configuration.
+// The rule is shared by all the lint sessions: use [RegisterRuleFunc] for a rule holding its configuration.
+// It fails if a rule, or an alias, with the same name is already registered.
+func RegisterRule(r lint.Rule) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	return registerRule(registeredRule{rule: r})
+}
+
+// RegisterRuleFunc is like [RegisterRule], but each lint session configures its own instance of the rule,
+// created by newRule, so that sessions with different configurations do not affect each other.
+func RegisterRuleFunc(newRule func() lint.Rule) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	return registerRule(registeredRule{rule: newRule(), newRule: newRule})
+}
+
+// RegisterDefaultRule is like [RegisterRule], but the rule is also enabled
+// when no configuration is provided or when enable-default-rules is set.
+func RegisterDefaultRule(r lint.Rule) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if err := registerRule(registeredRule{rule: r}); err != nil {
+		return err
+	}
+	defaultRuleNames[r.Name()] = true
+	return nil
+}
+
+func registerRule(r registeredRule) error {
+	name := r.rule.Name()
+	if _, ok := registeredRules[name]; ok {
+		return fmt.Errorf("duplicate rule name %q", name)
+	}
+	if target, ok := ruleAliases[name]; ok {
+		return fmt.Errorf("duplicate rule name %q, already an alias of rule %q", name, target)
+	}
+
+	registeredRules[name] = r
+	return nil
+}
+
+// RegisterRuleAlias registers an alternative name for the registered rule.
+func RegisterRuleAlias(alias, name string) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registeredRules[name]; !ok {
+		return fmt.Errorf("cannot alias unknown rule %q", name)
+	}
+	if _, ok := registeredRules[alias]; ok {
+		return fmt.Errorf("duplicate rule name %q", alias)
+	}
+	if target, ok := ruleAliases[alias]; ok {
+		return fmt.Errorf("duplicate rule name %q, already an alias of rule %q", alias, target)
+	}
+
+	ruleAliases[alias] = name
+	return nil
+}
+
+// ReplaceRule replaces the registered rule with the same name.
+// The replacement keeps the aliases of the rule, and whether it is a default rule.
+// As with [RegisterRule], the replacement is shared by all the lint sessions.
+func ReplaceRule(r lint.Rule) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name := r.Name()
+	if _, ok := registeredRules[name]; !ok {
+		return fmt.Errorf("cannot replace unknown rule %q", name)
+	}
+
+	registeredRules[name] = registeredRule{rule: r}
+	return nil
+}
+
+// UnregisterRule removes the named rule, and its aliases, from the registry.
+func UnregisterRule(name string) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name = actualRuleName(name)
+	if _, ok := registeredRules[name]; !ok {
+		return fmt.Errorf("cannot unregister unknown rule %q", name)
+	}
+
+	delete(registeredRules, name)
+	delete(defaultRuleNames, name)
+	for alias, target := range ruleAliases {
+		if target == name {
+			delete(ruleAliases, alias)
+		}
+	}
+	return nil
+}
+
+// LookupRule yields the rule registered with the given name or alias.
+func LookupRule(name string) (lint.Rule, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	r, ok := registeredRules[actualRuleName(name)]
+	return r.rule, ok
+}
+
+// newRegisteredRule yields the instance of the rule registered with the given name or alias
+// to configure for a lint session.
+func newRegisteredRule(name string) (lint.Rule, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	r, ok := registeredRules[actualRuleName(name)]
+	if !ok {
+		return nil, false
+	}
+	if r.newRule == nil {
+		return r.rule, true
+	}
+	return r.newRule(), true
+}
+
+// Rules yields all the registered rules, sorted by name.
+func Rules() []lint.Rule {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return sortedRules(func(string) bool { return true })
+}
+
+// DefaultRules yields the rules enabled when no configuration is provided, sorted by name.
+func DefaultRules() []lint.Rule {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return sortedRules(func(name string) bool { return defaultRuleNames[name] })
+}
+
+func sortedRules(keep func(name string) bool) []lint.Rule {
+	var result []lint.Rule
+	for name, r := range registeredRules {
+		if keep(name) {
+			result = append(result, r.rule)
+		}
+	}
+	slices.SortFunc(result, func(a, b lint.Rule) int {
+		return cmp.Compare(a.Name(), b.Name())
+	})
+	return result
+}
+
+// actualRuleName yields the name of the rule registered with the given name or alias.
+func actualRuleName(name string) string {
+	if target, ok := ruleAliases[name]; ok {
+		return target
+	}
+	return name
+}
+
+// RegisterFormatter adds the formatter to the registry, making it available with the -formatter flag.
+// The formatter must be a pointer, as each use gets a new instance of the type it points to.
+// It fails if a formatter with the same name is already registered.
+func RegisterFormatter(f lint.Formatter) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	name := f.Name()
+	if reflect.TypeOf(f).Kind() != reflect.Pointer {
+		return fmt.Errorf("formatter %q must be registered as a pointer, got %T", name, f)
+	}
+	if _, ok := registeredFormatters[name]; ok {
+		return fmt.Errorf("duplicate formatter name %q", name)
+	}
+
+	registeredFormatters[name] = f
+	return nil
+}
+
+// UnregisterFormatter removes the named formatter from the registry.
+func UnregisterFormatter(name string) error {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registeredFormatters[name]; !ok {
+		return fmt.Errorf("cannot unregister unknown formatter %q", name)
+	}
+
+	delete(registeredFormatters, name)
+	return nil
+}
+
+// Formatters yields all the registered formatters, sorted by name.
+func Formatters() []lint.Formatter {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	result := make([]lint.Formatter, 0, len(registeredFormatters))
+	for _, f := range registeredFormatters {
+		result = append(result, f)
+	}
+	slices.SortFunc(result, func(a, b lint.Formatter) int {
+		return cmp.Compare(a.Name(), b.Name())
+	})
+	return result
+}
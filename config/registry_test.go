@@ -0,0 +1,232 @@
+package config_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mgechev/revive/config"
+	"github.com/mgechev/revive/formatter"
+	"github.com/mgechev/revive/lint"
+	"github.com/mgechev/revive/rule"
+)
+
+type registryTestRule struct {
+	name string
+}
+
+func (r *registryTestRule) Name() string { return r.name }
+
+func (*registryTestRule) Apply(*lint.File, lint.Arguments) []lint.Failure { return nil }
+
+func ruleNames(rules []lint.Rule) []string {
+	names := make([]string, len(rules))
+	for i, r := range rules {
+		names[i] = r.Name()
+	}
+	return names
+}
+
+func TestRegistry_builtins(t *testing.T) {
+	if got := len(config.Rules()); got != 109 {
+		t.Errorf("expected 109 registered rules, got %d", got)
+	}
+	if got := len(config.DefaultRules()); got != 23 {
+		t.Errorf("expected 23 default rules, got %d", got)
+	}
+	if got := len(config.Formatters()); got != 13 {
+		t.Errorf("expected 13 registered formatters, got %d", got)
+	}
+
+	r, ok := config.LookupRule("imports-blacklist")
+	if !ok || r.Name() != "imports-blocklist" {
+		t.Errorf("expected the imports-blacklist alias to yield imports-blocklist, got %v", r)
+	}
+}
+
+func TestRegisterRule(t *testing.T) {
+	custom := &registryTestRule{name: "registry-test-rule"}
+	if err := config.RegisterDefaultRule(custom); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := config.UnregisterRule(custom.name); err != nil {
+			t.Error(err)
+		}
+	})
+
+	if err := config.RegisterRuleAlias("registry-test-alias", custom.name); err != nil {
+		t.Fatal(err)
+	}
+	if r, ok := config.LookupRule("registry-test-alias"); !ok || r != custom {
+		t.Errorf("expected lookup by alias to yield the registered rule, got %v", r)
+	}
+	if !slices.Contains(ruleNames(config.DefaultRules()), custom.name) {
+		t.Errorf("expected %q among the default rules", custom.name)
+	}
+
+	for name, register := range map[string]func() error{
+		"same name":       func() error { return config.RegisterRule(&registryTestRule{name: custom.name}) },
+		"alias name":      func() error { return config.RegisterRule(&registryTestRule{name: "registry-test-alias"}) },
+		"built-in name":   func() error { return config.RegisterRule(&registryTestRule{name: "exported"}) },
+		"duplicate alias": func() error { return config.RegisterRuleAlias("imports-blacklist", custom.name) },
+	} {
+		if err := register(); err == nil || !strings.Contains(err.Error(), "duplicate rule name") {
+			t.Errorf("%s: expected a duplicate rule name error, got %v", name, err)
+		}
+	}
+
+	replacement := &registryTestRule{name: custom.name}
+	if err := config.ReplaceRule(replacement); err != nil {
+		t.Fatal(err)
+	}
+	if r, _ := config.LookupRule(custom.name); r != replacement {
+		t.Errorf("expected the replacement rule, got %v", r)
+	}
+
+	conf := &lint.Config{Rules: lint.RulesConfig{"registry-test-alias": {}}}
+	rules, err := config.GetLintingRules(conf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 1 || rules[0] != replacement {
+		t.Errorf("expected the registered rule to be used, got %v", rules)
+	}
+}
+
+func TestRegisterRuleFunc(t *testing.T) {
+	const name = "registry-test-func"
+	if err := config.RegisterRuleFunc(func() lint.Rule { return &registryTestRule{name: name} }); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := config.UnregisterRule(name); err != nil {
+			t.Error(err)
+		}
+	})
+
+	conf := &lint.Config{Rules: lint.RulesConfig{name: {}, "argument-limit": {}}}
+	first, err := config.GetLintingRules(conf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := config.GetLintingRules(conf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range first {
+		registered, _ := config.LookupRule(r.Name())
+		if r == registered || slices.Contains(second, r) {
+			t.Errorf("expected each lint session to get its own instance of %s", r.Name())
+		}
+	}
+}
+
+func TestUnregisterRule(t *testing.T) {
+	custom := &registryTestRule{name: "registry-test-removed"}
+	if err := config.RegisterRule(custom); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.RegisterRuleAlias("registry-test-removed-alias", custom.name); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := config.UnregisterRule(custom.name); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{custom.name, "registry-test-removed-alias"} {
+		if _, ok := config.LookupRule(name); ok {
+			t.Errorf("expected %q to be unregistered", name)
+		}
+	}
+	if err := config.UnregisterRule(custom.name); err == nil {
+		t.Error("expected an error when unregistering an unknown rule")
+	}
+}
+
+func TestGetLintingRules_duplicateExtraRules(t *testing.T) {
+	conf := &lint.Config{Rules: lint.RulesConfig{"if-return": {}}}
+
+	if _, err := config.GetLintingRules(conf, []lint.Rule{&rule.IfReturnRule{}}); err != nil {
+		t.Errorf("expected an extra rule of the same type as the registered one to be accepted, got %v", err)
+	}
+
+	for name, extraRules := range map[string][]lint.Rule{
+		"registered name": {&registryTestRule{name: "if-return"}},
+		"extra rule name": {&registryTestRule{name: "registry-test-extra"}, &registryTestRule{name: "registry-test-extra"}},
+	} {
+		if _, err := config.GetLintingRules(conf, extraRules); err == nil || !strings.Contains(err.Error(), "duplicate rule name") {
+			t.Errorf("%s: expected a duplicate rule name error, got %v", name, err)
+		}
+	}
+}
+
+type registryTestFormatter struct {
+	formatter.Plain
+}
+
+func (*registryTestFormatter) Name() string { return "registry-test-formatter" }
+
+func TestRegisterFormatter(t *testing.T) {
+	if err := config.RegisterFormatter(&registryTestFormatter{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := config.UnregisterFormatter("registry-test-formatter"); err != nil {
+			t.Error(err)
+		}
+	})
+
+	f, err := config.NewFormatter("registry-test-formatter", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.(*registryTestFormatter); !ok {
+		t.Errorf("expected a new registryTestFormatter, got %T", f)
+	}
+
+	if err := config.RegisterFormatter(&formatter.Plain{}); err == nil || !strings.Contains(err.Error(), "duplicate formatter name") {
+		t.Errorf("expected a duplicate formatter name error, got %v", err)
+	}
+}
+
+type registryTestValueFormatter struct{}
+
+func (registryTestValueFormatter) Name() string { return "registry-test-value-formatter" }
+
+func (registryTestValueFormatter) Format(<-chan lint.Failure, lint.Config) (string, error) {
+	return "", nil
+}
+
+func TestRegisterFormatter_valueType(t *testing.T) {
+	err := config.RegisterFormatter(registryTestValueFormatter{})
+	if err == nil || !strings.Contains(err.Error(), "must be registered as a pointer") {
+		t.Errorf("expected an error for a formatter registered as a value, got %v", err)
+	}
+	if _, err := config.NewFormatter("registry-test-value-formatter", nil); err == nil {
+		t.Error("expected the formatter registered as a value to be unknown")
+	}
+}
+
+func TestNewFormatter_withoutDefault(t *testing.T) {
+	def, err := config.GetFormatter("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.UnregisterFormatter("default"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := config.RegisterFormatter(def); err != nil {
+			t.Error(err)
+		}
+	})
+
+	if _, err := config.GetFormatter(""); err == nil || !strings.Contains(err.Error(), "unknown formatter") {
+		t.Errorf("expected an unknown formatter error, got %v", err)
+	}
+	if _, err := config.NewFormatter("", nil); err == nil || !strings.Contains(err.Error(), "unknown formatter") {
+		t.Errorf("expected an unknown formatter error, got %v", err)
+	}
+}
@@ -1,8 +1,9 @@
 package formatter
 
 import (
-	"bytes"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/mgechev/revive/lint"
 )
@@ -13,23 +14,31 @@ type Default struct {
 	Metadata lint.FormatterMetadata
 }
 
+var _ lint.StreamingFormatter = (*Default)(nil)
+
 // Name returns the name of the formatter.
 func (*Default) Name() string {
 	return "default"
 }
 
 // Format formats the failures gotten from the lint.
-func (*Default) Format(failures <-chan lint.Failure, _ lint.Config) (string, error) {
-	var buf bytes.Buffer
-	prefix := ""
+func (f *Default) Format(failures <-chan lint.Failure, config lint.Config) (string, error) {
+	var sb strings.Builder
+	if err := f.FormatTo(&sb, failures, config); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+// FormatTo writes the failures gotten from the lint to w as they arrive.
+func (*Default) FormatTo(w io.Writer, failures <-chan lint.Failure, _ lint.Config) error {
 	for failure := range failures {
-		_, err := fmt.Fprintf(&buf, "%s%v: %s", prefix, failure.Position.Start, failure.Failure)
+		_, err := fmt.Fprintf(w, "%v: %s\n", failure.Position.Start, failure.Failure)
 		if err != nil {
-			return "", err
+			return err
 		}
-		prefix = "\n"
 	}
-	return buf.String(), nil
+	return nil
 }
 
 func ruleDescriptionURL(ruleName string) string {
@@ -4,6 +4,7 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/mgechev/revive/formatter"
@@ -511,6 +512,100 @@ Warnings:
 				`err.go:38:4: replace fmt.Errorf by errors.New https://revive.run/r#use-errors-new` +
 				"\n",
 		},
+		"rdjson": {
+			formatter: &formatter.RDJSON{},
+			failures: []lint.Failure{
+				{
+					Failure:  "error var Exp should have name of the form ErrFoo",
+					RuleName: "error-naming",
+					Category: lint.FailureCategoryNaming,
+					Position: lint.FailurePosition{
+						Start: token.Position{
+							Filename: "file.go",
+							Line:     2,
+							Column:   5,
+						},
+						End: token.Position{
+							Filename: "file.go",
+							Line:     2,
+							Column:   10,
+						},
+					},
+				},
+				{
+					Failure:         "should replace errors.New(fmt.Sprintf(...)) with fmt.Errorf(...)",
+					RuleName:        "use-errors-new",
+					Category:        lint.FailureCategoryErrors,
+					ReplacementLine: "\treturn fmt.Errorf(\"%d\", n)",
+					Position: lint.FailurePosition{
+						Start: token.Position{
+							Filename: "err.go",
+							Line:     33,
+							Column:   9,
+						},
+						End: token.Position{
+							Filename: "err.go",
+							Line:     33,
+							Column:   40,
+						},
+					},
+				},
+			},
+			want: `{"source":{"name":"revive","url":"https://revive.run"},"diagnostics":[` +
+				`{"message":"error var Exp should have name of the form ErrFoo","location":{"path":"file.go","range":{"start":{"line":2,"column":5},"end":{"line":2,"column":10}}},"severity":"WARNING","source":{"name":"revive","url":"https://revive.run"},"code":{"value":"error-naming","url":"https://revive.run/r#error-naming"}}` +
+				"," +
+				`{"message":"should replace errors.New(fmt.Sprintf(...)) with fmt.Errorf(...)","location":{"path":"err.go","range":{"start":{"line":33,"column":9},"end":{"line":33,"column":40}}},"severity":"ERROR","source":{"name":"revive","url":"https://revive.run"},"code":{"value":"use-errors-new","url":"https://revive.run/r#use-errors-new"},"suggestions":[{"range":{"start":{"line":33,"column":1},"end":{"line":34,"column":1}},"text":"\treturn fmt.Errorf(\"%d\", n)\n"}]}` +
+				"]}",
+		},
+		"rdjson no failures": {
+			formatter: &formatter.RDJSON{},
+			failures:  []lint.Failure{},
+			want:      `{"source":{"name":"revive","url":"https://revive.run"},"diagnostics":[]}`,
+		},
+		"rdjsonl": {
+			formatter: &formatter.RDJSONL{},
+			failures: []lint.Failure{
+				{
+					Failure:  "error var Exp should have name of the form ErrFoo",
+					RuleName: "error-naming",
+					Category: lint.FailureCategoryNaming,
+					Position: lint.FailurePosition{
+						Start: token.Position{
+							Filename: "file.go",
+							Line:     2,
+							Column:   5,
+						},
+						End: token.Position{
+							Filename: "file.go",
+							Line:     2,
+							Column:   10,
+						},
+					},
+				},
+				{
+					Failure:         "should replace errors.New(fmt.Sprintf(...)) with fmt.Errorf(...)",
+					RuleName:        "use-errors-new",
+					Category:        lint.FailureCategoryErrors,
+					ReplacementLine: "\treturn fmt.Errorf(\"%d\", n)",
+					Position: lint.FailurePosition{
+						Start: token.Position{
+							Filename: "err.go",
+							Line:     33,
+							Column:   9,
+						},
+						End: token.Position{
+							Filename: "err.go",
+							Line:     33,
+							Column:   40,
+						},
+					},
+				},
+			},
+			want: `{"message":"error var Exp should have name of the form ErrFoo","location":{"path":"file.go","range":{"start":{"line":2,"column":5},"end":{"line":2,"column":10}}},"severity":"WARNING","source":{"name":"revive","url":"https://revive.run"},"code":{"value":"error-naming","url":"https://revive.run/r#error-naming"}}` +
+				"\n" +
+				`{"message":"should replace errors.New(fmt.Sprintf(...)) with fmt.Errorf(...)","location":{"path":"err.go","range":{"start":{"line":33,"column":9},"end":{"line":33,"column":40}}},"severity":"ERROR","source":{"name":"revive","url":"https://revive.run"},"code":{"value":"use-errors-new","url":"https://revive.run/r#use-errors-new"},"suggestions":[{"range":{"start":{"line":33,"column":1},"end":{"line":34,"column":1}},"text":"\treturn fmt.Errorf(\"%d\", n)\n"}]}` +
+				"\n",
+		},
 		"sarif": {
 			formatter: &formatter.Sarif{},
 			failures: []lint.Failure{
@@ -650,6 +745,87 @@ Warnings:
   "version": "2.1.0"
 }`,
 		},
+		"stats": {
+			formatter: &formatter.Stats{Top: 1},
+			failures: []lint.Failure{
+				{
+					Failure:  "error var Exp should have name of the form ErrFoo",
+					RuleName: "error-naming",
+					Category: lint.FailureCategoryNaming,
+					Position: lint.FailurePosition{
+						Start: token.Position{
+							Filename: "pkg/file.go",
+							Line:     2,
+							Column:   5,
+						},
+						End: token.Position{
+							Filename: "pkg/file.go",
+							Line:     2,
+							Column:   10,
+						},
+					},
+				},
+				{
+					Failure:  "replace fmt.Errorf by errors.New",
+					RuleName: "use-errors-new",
+					Category: lint.FailureCategoryErrors,
+					Position: lint.FailurePosition{
+						Start: token.Position{
+							Filename: "err.go",
+							Line:     33,
+							Column:   4,
+						},
+						End: token.Position{
+							Filename: "err.go",
+							Line:     33,
+							Column:   8,
+						},
+					},
+				},
+				{
+					Failure:  "replace fmt.Errorf by errors.New",
+					RuleName: "use-errors-new",
+					Category: lint.FailureCategoryErrors,
+					Position: lint.FailurePosition{
+						Start: token.Position{
+							Filename: "err.go",
+							Line:     38,
+							Column:   4,
+						},
+						End: token.Position{
+							Filename: "err.go",
+							Line:     38,
+							Column:   9,
+						},
+					},
+				},
+			},
+			want: `3 failures
+
+By severity:
+  2  error
+  1  warning
+
+By rule:
+  2  use-errors-new
+  1  error-naming
+
+By category:
+  2  errors
+  1  naming
+
+By package (top 1):
+  2  .
+
+By file (top 1):
+  2  err.go
+`,
+		},
+		"stats no failures": {
+			formatter: &formatter.Stats{},
+			failures:  []lint.Failure{},
+			want:      "0 failures\n",
+		},
 		"stylish": {
 			formatter: &formatter.Stylish{},
 			failures: []lint.Failure{
@@ -774,6 +950,92 @@ file.go
 
  ✖ 1 problem (0 errors) (1 warning)`,
 		},
+		"template": {
+			formatter: &formatter.Template{
+				Text: `{{ .Filename }}|{{ .Position.Start.Line }}|{{ severity . }}|{{ .RuleName }}|{{ .Failure }}`,
+			},
+			failures: []lint.Failure{
+				{
+					Failure:  "error var Exp should have name of the form ErrFoo",
+					RuleName: "error-naming",
+					Category: lint.FailureCategoryNaming,
+					Position: lint.FailurePosition{
+						Start: token.Position{
+							Filename: "file.go",
+							Line:     2,
+							Column:   5,
+						},
+						End: token.Position{
+							Filename: "file.go",
+							Line:     2,
+							Column:   10,
+						},
+					},
+				},
+				{
+					Failure:  "replace fmt.Errorf by errors.New",
+					RuleName: "use-errors-new",
+					Category: lint.FailureCategoryErrors,
+					Position: lint.FailurePosition{
+						Start: token.Position{
+							Filename: "err.go",
+							Line:     33,
+							Column:   4,
+						},
+						End: token.Position{
+							Filename: "err.go",
+							Line:     33,
+							Column:   8,
+						},
+					},
+				},
+			},
+			want: "file.go|2|warning|error-naming|error var Exp should have name of the form ErrFoo\n" +
+				"err.go|33|error|use-errors-new|replace fmt.Errorf by errors.New\n",
+		},
+		"template report": {
+			formatter: &formatter.Template{
+				Text: `{{ define "report" }}{{ range $rule, $fs := groupBy "rule" . }}{{ $rule }}: {{ len $fs }}
+{{ end }}{{ json (index . 0).Position.Start.Line }}{{ end }}`,
+			},
+			failures: []lint.Failure{
+				{
+					Failure:  "error var Exp should have name of the form ErrFoo",
+					RuleName: "error-naming",
+					Category: lint.FailureCategoryNaming,
+					Position: lint.FailurePosition{
+						Start: token.Position{
+							Filename: "file.go",
+							Line:     2,
+							Column:   5,
+						},
+						End: token.Position{
+							Filename: "file.go",
+							Line:     2,
+							Column:   10,
+						},
+					},
+				},
+				{
+					Failure:  "replace fmt.Errorf by errors.New",
+					RuleName: "use-errors-new",
+					Category: lint.FailureCategoryErrors,
+					Position: lint.FailurePosition{
+						Start: token.Position{
+							Filename: "err.go",
+							Line:     33,
+							Column:   4,
+						},
+						End: token.Position{
+							Filename: "err.go",
+							Line:     33,
+							Column:   8,
+						},
+					},
+				},
+			},
+			want: "error-naming: 1\nuse-errors-new: 1\n2",
+		},
 		"unix": {
 			formatter: &formatter.Unix{},
 			failures: []lint.Failure{
@@ -881,3 +1143,191 @@ file.go
 		})
 	}
 }
+
+func TestStreamingFormatter(t *testing.T) {
+	failures := []lint.Failure{
+		{
+			Failure:  "error var Exp should have name of the form ErrFoo",
+			RuleName: "error-naming",
+			Category: lint.FailureCategoryNaming,
+			Position: lint.FailurePosition{
+				Start: token.Position{Filename: "file.go", Line: 2, Column: 5},
+				End:   token.Position{Filename: "file.go", Line: 2, Column: 10},
+			},
+		},
+		{
+			Failure:  "replace fmt.Errorf by errors.New",
+			RuleName: "use-errors-new",
+			Category: lint.FailureCategoryErrors,
+			Position: lint.FailurePosition{
+				Start: token.Position{Filename: "err.go", Line: 33, Column: 4},
+				End:   token.Position{Filename: "err.go", Line: 33, Column: 8},
+			},
+		},
+	}
+	for name, td := range map[string]struct {
+		formatter lint.StreamingFormatter
+		want      string
+	}{
+		"default": {
+			formatter: &formatter.Default{},
+			want:      "file.go:2:5: error var Exp should have name of the form ErrFoo\nerr.go:33:4: replace fmt.Errorf by errors.New\n",
+		},
+		"ndjson": {
+			formatter: &formatter.NDJSON{},
+			want: `{"Severity":"warning","Failure":"error var Exp should have name of the form ErrFoo","RuleName":"error-naming","Category":"naming","Position":{"Start":{"Filename":"file.go","Offset":0,"Line":2,"Column":5},"End":{"Filename":"file.go","Offset":0,"Line":2,"Column":10}},"Confidence":0,"ReplacementLine":""}` +
+				"\n" +
+				`{"Severity":"warning","Failure":"replace fmt.Errorf by errors.New","RuleName":"use-errors-new","Category":"errors","Position":{"Start":{"Filename":"err.go","Offset":0,"Line":33,"Column":4},"End":{"Filename":"err.go","Offset":0,"Line":33,"Column":8}},"Confidence":0,"ReplacementLine":""}` +
+				"\n",
+		},
+		"plain": {
+			formatter: &formatter.Plain{},
+			want:      "file.go:2:5: error var Exp should have name of the form ErrFoo https://revive.run/r#error-naming\nerr.go:33:4: replace fmt.Errorf by errors.New https://revive.run/r#use-errors-new\n",
+		},
+		"unix": {
+			formatter: &formatter.Unix{},
+			want:      "file.go:2:5: [error-naming] error var Exp should have name of the form ErrFoo\nerr.go:33:4: [use-errors-new] replace fmt.Errorf by errors.New\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			failuresChan := make(chan lint.Failure)
+			var sb strings.Builder
+			done := make(chan error)
+			go func() {
+				done <- td.formatter.FormatTo(&sb, failuresChan, lint.Config{})
+			}()
+
+			failuresChan <- failures[0]
+			failuresChan <- failures[1]
+			close(failuresChan)
+			if err := <-done; err != nil {
+				t.Fatal(err)
+			}
+
+			if got := sb.String(); got != td.want {
+				t.Errorf("got:\n%s\nwant:\n%s\n", got, td.want)
+			}
+		})
+	}
+}
+
+func TestStatsCompare(t *testing.T) {
+	previous := `[` +
+		`{"Severity":"error","Failure":"replace fmt.Errorf by errors.New","RuleName":"use-errors-new","Category":"errors","Position":{"Start":{"Filename":"err.go","Offset":0,"Line":30,"Column":4},"End":{"Filename":"err.go","Offset":0,"Line":30,"Column":8}},"Confidence":0,"ReplacementLine":""},` +
+		`{"Severity":"warning","Failure":"exported function Foo should have comment or be unexported","RuleName":"exported","Category":"comments","Position":{"Start":{"Filename":"foo.go","Offset":0,"Line":3,"Column":1},"End":{"Filename":"foo.go","Offset":0,"Line":3,"Column":8}},"Confidence":1,"ReplacementLine":""}` +
+		`]`
+	comparePath := filepath.Join(t.TempDir(), "previous.json")
+	if err := os.WriteFile(comparePath, []byte(previous), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	failure := lint.Failure{
+		Failure:  "replace fmt.Errorf by errors.New",
+		RuleName: "use-errors-new",
+		Category: lint.FailureCategoryErrors,
+		Position: lint.FailurePosition{
+			Start: token.Position{Filename: "err.go", Line: 33, Column: 4},
+			End:   token.Position{Filename: "err.go", Line: 33, Column: 8},
+		},
+	}
+
+	for name, td := range map[string]struct {
+		failures     []lint.Failure
+		wantDeltas   string
+		wantExitCode int
+	}{
+		"no increase": {
+			failures: []lint.Failure{failure},
+			wantDeltas: `  rule            previous  current  delta  new  fixed
+  use-errors-new  1         1        +0     0    0
+  exported        1         0        -1     0    1
+`,
+			wantExitCode: 0,
+		},
+		"increase": {
+			failures: []lint.Failure{failure, failure},
+			wantDeltas: `  rule            previous  current  delta  new  fixed
+  use-errors-new  1         2        +1     1    0
+  exported        1         0        -1     0    1
+`,
+			wantExitCode: 1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f := &formatter.Stats{}
+			if err := f.Configure(lint.FormatterArguments{"compare": comparePath}); err != nil {
+				t.Fatal(err)
+			}
+			failures := make(chan lint.Failure, len(td.failures))
+			for _, f := range td.failures {
+				failures <- f
+			}
+			close(failures)
+
+			output, err := f.Format(failures, lint.Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wantSuffix := "\nCompared to " + comparePath + ":\n" + td.wantDeltas
+			if !strings.HasSuffix(output, wantSuffix) {
+				t.Errorf("got:\n%s\nwant suffix:\n%s\n", output, wantSuffix)
+			}
+			if got := f.ExitCode(0); got != td.wantExitCode {
+				t.Errorf("got exit code %d, want %d", got, td.wantExitCode)
+			}
+		})
+	}
+}
+
+func TestShowSource(t *testing.T) {
+	t.Setenv("NO_COLOR", "true")
+	src := "package fixtures\n\nfunc f(n int) error {\n\treturn errors.New(fmt.Sprintf(\"%d\", n))\n}\n"
+	filename := filepath.Join(t.TempDir(), "err.go")
+	if err := os.WriteFile(filename, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	failure := lint.Failure{
+		Failure:         "should replace errors.New(fmt.Sprintf(...)) with fmt.Errorf(...)",
+		RuleName:        "errorf",
+		Category:        lint.FailureCategoryErrors,
+		ReplacementLine: "\treturn fmt.Errorf(\"%d\", n)",
+		Position: lint.FailurePosition{
+			Start: token.Position{Filename: filename, Line: 4, Column: 9},
+			End:   token.Position{Filename: filename, Line: 4, Column: 40},
+		},
+	}
+	wantSnippet := "    4 | \treturn errors.New(fmt.Sprintf(\"%d\", n))\n" +
+		"      | \t       ^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^^\n" +
+		"      - \treturn errors.New(fmt.Sprintf(\"%d\", n))\n" +
+		"      + \treturn fmt.Errorf(\"%d\", n)\n"
+
+	for name, f := range map[string]lint.Formatter{
+		"friendly": &formatter.Friendly{},
+		"stylish":  &formatter.Stylish{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := f.(lint.ConfigurableFormatter).Configure(lint.FormatterArguments{"show-source": "true"}); err != nil {
+				t.Fatal(err)
+			}
+			failures := make(chan lint.Failure, 1)
+			failures <- failure
+			close(failures)
+
+			output, err := f.Format(failures, lint.Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(output, wantSnippet) {
+				t.Errorf("got:\n%s\nwant to contain:\n%s\n", output, wantSnippet)
+			}
+		})
+	}
+
+	t.Run("invalid argument", func(t *testing.T) {
+		err := (&formatter.Friendly{}).Configure(lint.FormatterArguments{"show-source": "maybe"})
+		if err == nil {
+			t.Error("Expected an error for a non-boolean show-source argument")
+		}
+	})
+}
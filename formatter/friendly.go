@@ -18,13 +18,31 @@ import (
 // which formats the errors to a friendly, human-readable format.
 type Friendly struct {
 	Metadata lint.FormatterMetadata
+	// ShowSource enables printing the source code of each failure.
+	ShowSource bool
+
+	sources *sourceSnippets
 }
 
+var _ lint.ConfigurableFormatter = (*Friendly)(nil)
+
 // Name returns the name of the formatter.
 func (*Friendly) Name() string {
 	return "friendly"
 }
 
+// Configure validates the formatter configuration, and configures the formatter accordingly.
+//
+// Configuration implements the [lint.ConfigurableFormatter] interface.
+func (f *Friendly) Configure(arguments lint.FormatterArguments) error {
+	showSource, err := parseShowSource(arguments, f.ShowSource)
+	if err != nil {
+		return err
+	}
+	f.ShowSource = showSource
+	return nil
+}
+
 // Format formats the failures gotten from the lint.
 func (f *Friendly) Format(failures <-chan lint.Failure, config lint.Config) (string, error) {
 	var buf strings.Builder
@@ -34,6 +52,9 @@ func (f *Friendly) Format(failures <-chan lint.Failure, config lint.Config) (str
 	totalWarnings := 0
 	warningEmoji := color.YellowString("⚠")
 	errorEmoji := color.RedString("✘")
+	if f.ShowSource {
+		f.sources = newSourceSnippets()
+	}
 	for failure := range failures {
 		sev := severity(config, failure)
 		firstCol := warningEmoji
@@ -74,6 +95,11 @@ func (f *Friendly) printFriendlyFailure(sb *strings.Builder, firstColumn string,
 	if err := f.printFilePosition(sb, failure); err != nil {
 		return err
 	}
+	if f.sources != nil {
+		if snippet := f.sources.render(failure, "    "); snippet != "" {
+			sb.WriteString("\n" + strings.TrimSuffix(snippet, "\n"))
+		}
+	}
 	_, err := sb.WriteString("\n\n")
 	return err
 }
@@ -1,8 +1,9 @@
 package formatter
 
 import (
-	"bytes"
 	"encoding/json"
+	"io"
+	"strings"
 
 	"github.com/mgechev/revive/lint"
 )
@@ -13,23 +14,33 @@ type NDJSON struct {
 	Metadata lint.FormatterMetadata
 }
 
+var _ lint.StreamingFormatter = (*NDJSON)(nil)
+
 // Name returns the name of the formatter.
 func (*NDJSON) Name() string {
 	return "ndjson"
 }
 
 // Format formats the failures gotten from the lint.
-func (*NDJSON) Format(failures <-chan lint.Failure, config lint.Config) (string, error) {
-	var buf bytes.Buffer
-	enc := json.NewEncoder(&buf)
+func (f *NDJSON) Format(failures <-chan lint.Failure, config lint.Config) (string, error) {
+	var sb strings.Builder
+	if err := f.FormatTo(&sb, failures, config); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// FormatTo writes the failures gotten from the lint to w as they arrive.
+func (*NDJSON) FormatTo(w io.Writer, failures <-chan lint.Failure, config lint.Config) error {
+	enc := json.NewEncoder(w)
 	for failure := range failures {
 		obj := jsonObject{}
 		obj.Severity = severity(config, failure)
 		obj.Failure = failure
 		err := enc.Encode(obj)
 		if err != nil {
-			return "", err
+			return err
 		}
 	}
-	return buf.String(), nil
+	return nil
 }
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/mgechev/revive/lint"
@@ -13,19 +14,29 @@ type Plain struct {
 	Metadata lint.FormatterMetadata
 }
 
+var _ lint.StreamingFormatter = (*Plain)(nil)
+
 // Name returns the name of the formatter.
 func (*Plain) Name() string {
 	return "plain"
 }
 
 // Format formats the failures gotten from the lint.
-func (*Plain) Format(failures <-chan lint.Failure, _ lint.Config) (string, error) {
+func (f *Plain) Format(failures <-chan lint.Failure, config lint.Config) (string, error) {
 	var sb strings.Builder
+	if err := f.FormatTo(&sb, failures, config); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// FormatTo writes the failures gotten from the lint to w as they arrive.
+func (*Plain) FormatTo(w io.Writer, failures <-chan lint.Failure, _ lint.Config) error {
 	for failure := range failures {
-		_, err := fmt.Fprintf(&sb, "%v: %s %s\n", failure.Position.Start, failure.Failure, ruleDescriptionURL(failure.RuleName))
+		_, err := fmt.Fprintf(w, "%v: %s %s\n", failure.Position.Start, failure.Failure, ruleDescriptionURL(failure.RuleName))
 		if err != nil {
-			return "", err
+			return err
 		}
 	}
-	return sb.String(), nil
+	return nil
 }
@@ -0,0 +1,153 @@
+package formatter
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/mgechev/revive/lint"
+)
+
+// RDJSON is an implementation of the [lint.Formatter] interface
+// which formats the errors to reviewdog's Diagnostic JSON format (rdjson).
+//
+// See https://github.com/reviewdog/reviewdog/tree/master/proto/rdf.
+type RDJSON struct {
+	Metadata lint.FormatterMetadata
+}
+
+// Name returns the name of the formatter.
+func (*RDJSON) Name() string {
+	return "rdjson"
+}
+
+// Format formats the failures gotten from the lint.
+func (*RDJSON) Format(failures <-chan lint.Failure, config lint.Config) (string, error) {
+	result := rdResult{
+		Source:      reviveRDSource,
+		Diagnostics: []rdDiagnostic{},
+	}
+	for failure := range failures {
+		result.Diagnostics = append(result.Diagnostics, newRDDiagnostic(failure, config))
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// RDJSONL is an implementation of the [lint.Formatter] interface
+// which formats the errors to reviewdog's Diagnostic JSON Lines format (rdjsonl),
+// one diagnostic per line.
+//
+// See https://github.com/reviewdog/reviewdog/tree/master/proto/rdf.
+type RDJSONL struct {
+	Metadata lint.FormatterMetadata
+}
+
+var _ lint.StreamingFormatter = (*RDJSONL)(nil)
+
+// Name returns the name of the formatter.
+func (*RDJSONL) Name() string {
+	return "rdjsonl"
+}
+
+// Format formats the failures gotten from the lint.
+func (f *RDJSONL) Format(failures <-chan lint.Failure, config lint.Config) (string, error) {
+	var sb strings.Builder
+	if err := f.FormatTo(&sb, failures, config); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// FormatTo writes the failures gotten from the lint to w as they arrive.
+func (*RDJSONL) FormatTo(w io.Writer, failures <-chan lint.Failure, config lint.Config) error {
+	enc := json.NewEncoder(w)
+	for failure := range failures {
+		if err := enc.Encode(newRDDiagnostic(failure, config)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var reviveRDSource = rdSource{Name: "revive", URL: reviveSite}
+
+// rdResult is the reviewdog DiagnosticResult message.
+type rdResult struct {
+	Source      rdSource       `json:"source"`
+	Diagnostics []rdDiagnostic `json:"diagnostics"`
+}
+
+// rdDiagnostic is the reviewdog Diagnostic message.
+type rdDiagnostic struct {
+	Message     string         `json:"message"`
+	Location    rdLocation     `json:"location"`
+	Severity    string         `json:"severity"`
+	Source      rdSource       `json:"source"`
+	Code        rdCode         `json:"code"`
+	Suggestions []rdSuggestion `json:"suggestions,omitempty"`
+}
+
+type rdSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type rdCode struct {
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+type rdLocation struct {
+	Path  string  `json:"path"`
+	Range rdRange `json:"range"`
+}
+
+type rdRange struct {
+	Start rdPosition  `json:"start"`
+	End   *rdPosition `json:"end,omitempty"`
+}
+
+type rdPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column,omitempty"`
+}
+
+type rdSuggestion struct {
+	Range rdRange `json:"range"`
+	Text  string  `json:"text"`
+}
+
+func newRDDiagnostic(failure lint.Failure, config lint.Config) rdDiagnostic {
+	start := failure.Position.Start
+	end := failure.Position.End
+
+	rng := rdRange{Start: rdPosition{Line: start.Line, Column: start.Column}}
+	if end.Line > 0 {
+		rng.End = &rdPosition{Line: end.Line, Column: end.Column}
+	}
+
+	diagnostic := rdDiagnostic{
+		Message:  failure.Failure,
+		Location: rdLocation{Path: failure.Filename(), Range: rng},
+		Severity: strings.ToUpper(string(severity(config, failure))),
+		Source:   reviveRDSource,
+		Code:     rdCode{Value: failure.RuleName, URL: ruleDescriptionURL(failure.RuleName)},
+	}
+
+	if failure.ReplacementLine != "" && start.Line > 0 {
+		// the replacement line replaces the whole line where the failure starts
+		diagnostic.Suggestions = []rdSuggestion{{
+			Range: rdRange{
+				Start: rdPosition{Line: start.Line, Column: 1},
+				End:   &rdPosition{Line: start.Line + 1, Column: 1},
+			},
+			Text: strings.TrimSuffix(failure.ReplacementLine, "\n") + "\n",
+		}}
+	}
+
+	return diagnostic
+}
@@ -0,0 +1,105 @@
+package formatter
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/mgechev/revive/lint"
+)
+
+// showSourceArgument is the name of the formatter argument enabling code context.
+const showSourceArgument = "show-source"
+
+// parseShowSource parses the show-source argument of a formatter, if present.
+func parseShowSource(arguments lint.FormatterArguments, current bool) (bool, error) {
+	value, ok := arguments[showSourceArgument]
+	if !ok {
+		return current, nil
+	}
+	show, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value %q for %s, expected a boolean", value, showSourceArgument)
+	}
+	return show, nil
+}
+
+// sourceSnippets renders the source code of failures, reading each file at most once.
+type sourceSnippets struct {
+	files map[string][]string
+}
+
+func newSourceSnippets() *sourceSnippets {
+	return &sourceSnippets{files: map[string][]string{}}
+}
+
+func (s *sourceSnippets) lines(filename string) []string {
+	lines, ok := s.files[filename]
+	if ok {
+		return lines
+	}
+	content, err := os.ReadFile(filename) //nolint:gosec // ignore G304: potential file inclusion via variable
+	if err == nil {
+		lines = strings.Split(string(content), "\n")
+	}
+	s.files[filename] = lines // nil if the file can't be read, to avoid reading it again
+	return lines
+}
+
+// render returns the source lines of the failure, each followed by a line underlining
+// the span from the start to the end of the failure, and the suggested replacement line
+// as a diff, if any. It returns an empty string if the source is not available.
+func (s *sourceSnippets) render(failure lint.Failure, indent string) string {
+	start, end := failure.Position.Start, failure.Position.End
+	lines := s.lines(failure.Filename())
+	if start.Line < 1 || start.Line > len(lines) {
+		return ""
+	}
+	if end.Line < start.Line || end.Line > len(lines) {
+		end = start
+	}
+
+	gutterWidth := len(strconv.Itoa(end.Line))
+	var sb strings.Builder
+	for n := start.Line; n <= end.Line; n++ {
+		line := strings.TrimSuffix(lines[n-1], "\r")
+		from, to := 1, len(line)+1
+		if n == start.Line {
+			from = start.Column
+		}
+		if n == end.Line && end != start {
+			to = end.Column
+		}
+		fmt.Fprintf(&sb, "%s%*d | %s\n", indent, gutterWidth, n, line)
+		fmt.Fprintf(&sb, "%s%*s | %s\n", indent, gutterWidth, "", color.RedString(underline(line, from, to)))
+	}
+
+	if failure.ReplacementLine != "" {
+		original := strings.TrimSuffix(lines[start.Line-1], "\r")
+		replacement := strings.TrimSuffix(failure.ReplacementLine, "\n")
+		fmt.Fprintf(&sb, "%s%*s %s\n", indent, gutterWidth, "", color.RedString("- "+original))
+		fmt.Fprintf(&sb, "%s%*s %s\n", indent, gutterWidth, "", color.GreenString("+ "+replacement))
+	}
+
+	return sb.String()
+}
+
+// underline returns a line of carets spanning the columns [from, to) of line,
+// preserving tabs before the carets so they stay aligned with the source.
+func underline(line string, from, to int) string {
+	from = min(max(from, 1), len(line)+1)
+	to = min(to, len(line)+1)
+	var sb strings.Builder
+	for _, c := range line[:from-1] {
+		if c == '\t' {
+			sb.WriteByte('\t')
+		} else {
+			sb.WriteByte(' ')
+		}
+	}
+	sb.WriteString(strings.Repeat("^", max(to-from, 1)))
+	return sb.String()
+}
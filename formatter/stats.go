@@ -0,0 +1,226 @@
+package formatter
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mgechev/revive/lint"
+)
+
+// Stats is an implementation of the [lint.Formatter] interface
+// which summarizes the failures by severity, rule, category, package and file.
+//
+// When configured with the output of the [JSON] formatter from a previous run,
+// it also reports the per-rule differences with that run, and its exit code
+// is non-zero only if the number of failures of any rule increased.
+// Along with other formatters, it only raises the exit code when the number of failures increased.
+type Stats struct {
+	Metadata lint.FormatterMetadata
+	// Compare is the path to the output of the json formatter of a previous run.
+	Compare string
+	// Top is the maximum number of packages and files listed; zero means the default.
+	Top int
+
+	increased bool
+}
+
+var (
+	_ lint.ConfigurableFormatter = (*Stats)(nil)
+	_ lint.ExitCodeFormatter     = (*Stats)(nil)
+)
+
+const defaultStatsTop = 10
+
+// Name returns the name of the formatter.
+func (*Stats) Name() string {
+	return "stats"
+}
+
+// Configure validates the formatter configuration, and configures the formatter accordingly.
+//
+// Configuration implements the [lint.ConfigurableFormatter] interface.
+func (s *Stats) Configure(arguments lint.FormatterArguments) error {
+	if compare, ok := arguments["compare"]; ok {
+		s.Compare = compare
+	}
+	if top, ok := arguments["top"]; ok {
+		n, err := strconv.Atoi(top)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid value %q for top, expected a positive integer", top)
+		}
+		s.Top = n
+	}
+	return nil
+}
+
+// ExitCode returns the computed exit code unless the formatter compares with a previous run,
+// in which case it is non-zero only if the number of failures of any rule increased.
+// The exit code is lowered only if stats is the sole output, see [lint.ExitCodeFormatter].
+//
+// ExitCode implements the [lint.ExitCodeFormatter] interface.
+func (s *Stats) ExitCode(computed int) int {
+	switch {
+	case s.Compare == "":
+		return computed
+	case !s.increased:
+		return 0
+	case computed == 0:
+		return 1
+	default:
+		return computed
+	}
+}
+
+// failureKey identifies a failure across runs regardless of its position in the file.
+type failureKey struct {
+	file    string
+	rule    string
+	failure string
+}
+
+// Format formats the failures gotten from the lint.
+func (s *Stats) Format(failures <-chan lint.Failure, config lint.Config) (string, error) {
+	var previous []jsonObject
+	if s.Compare != "" {
+		content, err := os.ReadFile(s.Compare)
+		if err != nil {
+			return "", fmt.Errorf("reading previous run: %w", err)
+		}
+		if err := json.Unmarshal(content, &previous); err != nil {
+			return "", fmt.Errorf("parsing previous run %s: %w", s.Compare, err)
+		}
+	}
+
+	top := s.Top
+	if top == 0 {
+		top = defaultStatsTop
+	}
+
+	total := 0
+	bySeverity := map[string]int{}
+	byRule := map[string]int{}
+	byCategory := map[string]int{}
+	byPackage := map[string]int{}
+	byFile := map[string]int{}
+	current := map[failureKey]int{}
+	for failure := range failures {
+		total++
+		bySeverity[string(severity(config, failure))]++
+		byRule[failure.RuleName]++
+		byCategory[cmp.Or(string(failure.Category), "none")]++
+		byPackage[filepath.Dir(failure.Filename())]++
+		byFile[failure.Filename()]++
+		current[failureKey{failure.Filename(), failure.RuleName, failure.Failure}]++
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d failures\n", total)
+	writeStatsSection(&sb, "By severity:", bySeverity, 0)
+	writeStatsSection(&sb, "By rule:", byRule, 0)
+	writeStatsSection(&sb, "By category:", byCategory, 0)
+	writeStatsSection(&sb, fmt.Sprintf("By package (top %d):", top), byPackage, top)
+	writeStatsSection(&sb, fmt.Sprintf("By file (top %d):", top), byFile, top)
+
+	if s.Compare != "" {
+		s.writeComparison(&sb, previous, current)
+	}
+
+	return sb.String(), nil
+}
+
+// ruleDelta is the difference between the failures of a rule in two runs.
+type ruleDelta struct {
+	rule     string
+	previous int
+	current  int
+	added    int
+	fixed    int
+}
+
+func (s *Stats) writeComparison(sb *strings.Builder, previous []jsonObject, current map[failureKey]int) {
+	before := map[failureKey]int{}
+	for _, obj := range previous {
+		before[failureKey{obj.Filename(), obj.RuleName, obj.Failure.Failure}]++
+	}
+
+	deltas := map[string]*ruleDelta{}
+	deltaOf := func(rule string) *ruleDelta {
+		d, ok := deltas[rule]
+		if !ok {
+			d = &ruleDelta{rule: rule}
+			deltas[rule] = d
+		}
+		return d
+	}
+	for key, n := range current {
+		d := deltaOf(key.rule)
+		d.current += n
+		d.added += max(0, n-before[key])
+	}
+	for key, n := range before {
+		d := deltaOf(key.rule)
+		d.previous += n
+		d.fixed += max(0, n-current[key])
+	}
+
+	sorted := make([]*ruleDelta, 0, len(deltas))
+	for _, d := range deltas {
+		sorted = append(sorted, d)
+	}
+	slices.SortFunc(sorted, func(a, b *ruleDelta) int {
+		return cmp.Or(
+			-cmp.Compare(a.current-a.previous, b.current-b.previous),
+			cmp.Compare(a.rule, b.rule),
+		)
+	})
+
+	rows := [][]string{{"rule", "previous", "current", "delta", "new", "fixed"}}
+	for _, d := range sorted {
+		if d.current > d.previous {
+			s.increased = true
+		}
+		rows = append(rows, []string{
+			d.rule,
+			strconv.Itoa(d.previous),
+			strconv.Itoa(d.current),
+			fmt.Sprintf("%+d", d.current-d.previous),
+			strconv.Itoa(d.added),
+			strconv.Itoa(d.fixed),
+		})
+	}
+
+	fmt.Fprintf(sb, "\nCompared to %s:\n", s.Compare)
+	sb.WriteString(table(rows))
+}
+
+// writeStatsSection writes the entries of stats sorted by decreasing count,
+// keeping at most limit entries if limit is greater than zero.
+func writeStatsSection(sb *strings.Builder, header string, stats map[string]int, limit int) {
+	if len(stats) == 0 {
+		return
+	}
+
+	data := make([]statEntry, 0, len(stats))
+	for name, total := range stats {
+		data = append(data, statEntry{name, total})
+	}
+	slices.SortFunc(data, func(a, b statEntry) int {
+		return cmp.Or(-cmp.Compare(a.failures, b.failures), cmp.Compare(a.name, b.name))
+	})
+	if limit > 0 && len(data) > limit {
+		data = data[:limit]
+	}
+
+	rows := make([][]string, 0, len(data))
+	for _, entry := range data {
+		rows = append(rows, []string{strconv.Itoa(entry.failures), entry.name})
+	}
+	fmt.Fprintf(sb, "\n%s\n", header)
+	sb.WriteString(table(rows))
+}
@@ -3,6 +3,7 @@ package formatter
 import (
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/fatih/color"
 
@@ -13,9 +14,14 @@ import (
 // which formats the errors to a stylish, human-readable format.
 type Stylish struct {
 	Metadata lint.FormatterMetadata
+	// ShowSource enables printing the source code of each failure.
+	ShowSource bool
 }
 
-var _ lint.Formatter = (*Stylish)(nil)
+var (
+	_ lint.Formatter             = (*Stylish)(nil)
+	_ lint.ConfigurableFormatter = (*Stylish)(nil)
+)
 
 // Name returns the name of the formatter.
 func (*Stylish) Name() string {
@@ -34,9 +40,26 @@ func formatFailure(failure lint.Failure, severity lint.Severity) []string {
 	return []string{failure.Filename(), pos, fName, fString}
 }
 
+// Configure validates the formatter configuration, and configures the formatter accordingly.
+//
+// Configuration implements the [lint.ConfigurableFormatter] interface.
+func (s *Stylish) Configure(arguments lint.FormatterArguments) error {
+	showSource, err := parseShowSource(arguments, s.ShowSource)
+	if err != nil {
+		return err
+	}
+	s.ShowSource = showSource
+	return nil
+}
+
 // Format formats the failures gotten from the lint.
-func (*Stylish) Format(failures <-chan lint.Failure, config lint.Config) (string, error) {
+func (s *Stylish) Format(failures <-chan lint.Failure, config lint.Config) (string, error) {
 	var result [][]string
+	var snippets []string
+	var sources *sourceSnippets
+	if s.ShowSource {
+		sources = newSourceSnippets()
+	}
 	totalErrors := 0
 	total := 0
 
@@ -47,18 +70,25 @@ func (*Stylish) Format(failures <-chan lint.Failure, config lint.Config) (string
 			totalErrors++
 		}
 		result = append(result, formatFailure(f, currentType))
+		if sources != nil {
+			snippets = append(snippets, sources.render(f, "    "))
+		}
 	}
 
 	fileReport := map[string][][]string{}
+	fileSnippets := map[string][]string{}
 	var files []string
 
-	for _, row := range result {
+	for i, row := range result {
 		if _, ok := fileReport[row[0]]; !ok {
 			fileReport[row[0]] = [][]string{}
 			files = append(files, row[0])
 		}
 
 		fileReport[row[0]] = append(fileReport[row[0]], []string{row[1], row[2], row[3]})
+		if sources != nil {
+			fileSnippets[row[0]] = append(fileSnippets[row[0]], snippets[i])
+		}
 	}
 	slices.Sort(files)
 
@@ -66,7 +96,16 @@ func (*Stylish) Format(failures <-chan lint.Failure, config lint.Config) (string
 	for _, filename := range files {
 		c := color.New(color.Underline)
 		output += c.SprintfFunc()(filename + "\n")
-		output += table(fileReport[filename]) + "\n"
+		if sources == nil {
+			output += table(fileReport[filename]) + "\n"
+			continue
+		}
+		// keep the rows aligned as a single table, with each row followed by its source
+		rows := strings.SplitAfter(table(fileReport[filename]), "\n")
+		for i, snippet := range fileSnippets[filename] {
+			output += rows[i] + snippet
+		}
+		output += "\n"
 	}
 
 	problemsLabel := "problems"
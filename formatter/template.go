@@ -0,0 +1,146 @@
+package formatter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/mgechev/revive/lint"
+)
+
+// Template is an implementation of the [lint.Formatter] interface
+// which formats the errors with a user-defined [text/template].
+//
+// The template is executed once per failure, with a pointer to the [lint.Failure] as data.
+// If the template defines a template named "report", that template is instead
+// executed once with the slice of all failures as data.
+type Template struct {
+	Metadata lint.FormatterMetadata
+	// Text is the source of the template.
+	Text string
+}
+
+var _ lint.ConfigurableFormatter = (*Template)(nil)
+
+// templateReportName is the name of the template executed over the whole list of failures.
+const templateReportName = "report"
+
+// Name returns the name of the formatter.
+func (*Template) Name() string {
+	return "template"
+}
+
+// Configure validates the formatter configuration, and configures the formatter accordingly.
+//
+// The "template" argument is either an inline template (if it contains an action delimiter)
+// or the path to a file containing the template.
+//
+// Configuration implements the [lint.ConfigurableFormatter] interface.
+func (t *Template) Configure(arguments lint.FormatterArguments) error {
+	text, ok := arguments["template"]
+	if !ok {
+		return nil
+	}
+
+	if strings.Contains(text, "{{") {
+		t.Text = text
+		return nil
+	}
+
+	content, err := os.ReadFile(text) //nolint:gosec // ignore G304: potential file inclusion via variable
+	if err != nil {
+		return fmt.Errorf("reading template file: %w", err)
+	}
+	t.Text = string(content)
+	return nil
+}
+
+// Format formats the failures gotten from the lint.
+func (t *Template) Format(failures <-chan lint.Failure, config lint.Config) (string, error) {
+	if t.Text == "" {
+		return "", errors.New("the template formatter requires a template, set it with -formatter-template")
+	}
+
+	tmpl, err := template.New(t.Name()).Funcs(templateFuncs(config)).Parse(t.Text)
+	if err != nil {
+		return "", fmt.Errorf("parsing template: %w", err)
+	}
+
+	var sb strings.Builder
+	if report := tmpl.Lookup(templateReportName); report != nil {
+		var all []lint.Failure
+		for failure := range failures {
+			all = append(all, failure)
+		}
+		if err := report.Execute(&sb, all); err != nil {
+			return "", err
+		}
+		return sb.String(), nil
+	}
+
+	for failure := range failures {
+		start := sb.Len()
+		if err := tmpl.Execute(&sb, &failure); err != nil {
+			return "", err
+		}
+		if sb.Len() > start && !strings.HasSuffix(sb.String(), "\n") {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String(), nil
+}
+
+// templateFuncs returns the helper functions available in templates.
+func templateFuncs(config lint.Config) template.FuncMap {
+	return template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+		"relpath": func(path string) string {
+			wd, err := os.Getwd()
+			if err != nil {
+				return path
+			}
+			rel, err := filepath.Rel(wd, path)
+			if err != nil {
+				return path
+			}
+			return rel
+		},
+		"severity": func(failure lint.Failure) lint.Severity {
+			return severity(config, failure)
+		},
+		"groupBy": func(key string, failures []lint.Failure) (map[string][]lint.Failure, error) {
+			return groupFailuresBy(key, failures, config)
+		},
+	}
+}
+
+// groupFailuresBy groups failures by file, rule, category or severity.
+func groupFailuresBy(key string, failures []lint.Failure, config lint.Config) (map[string][]lint.Failure, error) {
+	var keyOf func(lint.Failure) string
+	switch key {
+	case "file":
+		keyOf = func(f lint.Failure) string { return f.Filename() }
+	case "rule":
+		keyOf = func(f lint.Failure) string { return f.RuleName }
+	case "category":
+		keyOf = func(f lint.Failure) string { return string(f.Category) }
+	case "severity":
+		keyOf = func(f lint.Failure) string { return string(severity(config, f)) }
+	default:
+		return nil, fmt.Errorf("unknown group key %q, expected one of file, rule, category, severity", key)
+	}
+
+	groups := map[string][]lint.Failure{}
+	for _, f := range failures {
+		k := keyOf(f)
+		groups[k] = append(groups[k], f)
+	}
+	return groups, nil
+}
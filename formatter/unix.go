@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/mgechev/revive/lint"
@@ -15,19 +16,29 @@ type Unix struct {
 	Metadata lint.FormatterMetadata
 }
 
+var _ lint.StreamingFormatter = (*Unix)(nil)
+
 // Name returns the name of the formatter.
 func (*Unix) Name() string {
 	return "unix"
 }
 
 // Format formats the failures gotten from the lint.
-func (*Unix) Format(failures <-chan lint.Failure, _ lint.Config) (string, error) {
+func (f *Unix) Format(failures <-chan lint.Failure, config lint.Config) (string, error) {
 	var sb strings.Builder
+	if err := f.FormatTo(&sb, failures, config); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// FormatTo writes the failures gotten from the lint to w as they arrive.
+func (*Unix) FormatTo(w io.Writer, failures <-chan lint.Failure, _ lint.Config) error {
 	for failure := range failures {
-		_, err := fmt.Fprintf(&sb, "%v: [%s] %s\n", failure.Position.Start, failure.RuleName, failure.Failure)
+		_, err := fmt.Fprintf(w, "%v: [%s] %s\n", failure.Position.Start, failure.RuleName, failure.Failure)
 		if err != nil {
-			return "", err
+			return err
 		}
 	}
-	return sb.String(), nil
+	return nil
 }
@@ -0,0 +1,246 @@
+// Package astpattern matches Go syntax trees against gogrep-style patterns.
+//
+// A pattern is a Go expression or statement where $name stands for any expression (or statement),
+// and $*name for any number of elements in a list, such as call arguments.
+// A variable used several times must match identical code every time; $_ matches anything without binding.
+package astpattern
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+const (
+	varPrefix  = "__astpattern_var_"
+	listPrefix = "__astpattern_list_"
+	anyName    = "_"
+)
+
+var variableRegexp = regexp.MustCompile(`\$(\*?)([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Pattern is a compiled pattern.
+type Pattern struct {
+	node ast.Node
+	// Variables are the names of the variables of the pattern, in order of appearance.
+	Variables []string
+}
+
+// Captures maps the variables of a pattern to the nodes they matched.
+// A $name variable matches exactly one node, a $*name variable any number of nodes.
+type Captures map[string][]ast.Node
+
+// Compile parses the pattern.
+func Compile(pattern string) (*Pattern, error) {
+	var variables []string
+	seen := map[string]bool{}
+	src := variableRegexp.ReplaceAllStringFunc(pattern, func(v string) string {
+		m := variableRegexp.FindStringSubmatch(v)
+		isList, name := m[1] != "", m[2]
+		if name != anyName && !seen[name] {
+			seen[name] = true
+			variables = append(variables, name)
+		}
+		if isList {
+			return listPrefix + name
+		}
+		return varPrefix + name
+	})
+
+	if expr, err := parser.ParseExpr(src); err == nil {
+		return &Pattern{node: expr, Variables: variables}, nil
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p; func _() { "+src+"\n}", 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	body := file.Decls[0].(*ast.FuncDecl).Body.List
+	if len(body) != 1 {
+		return nil, fmt.Errorf("invalid pattern %q: expected a single expression or statement, got %d statements", pattern, len(body))
+	}
+	return &Pattern{node: body[0], Variables: variables}, nil
+}
+
+// Match reports whether the node matches the pattern, and the nodes captured by its variables.
+func (p *Pattern) Match(node ast.Node) (Captures, bool) {
+	if node == nil {
+		return nil, false
+	}
+	m := &matcher{captures: Captures{}}
+	if !m.matchNode(p.node, node) {
+		return nil, false
+	}
+	return m.captures, true
+}
+
+type matcher struct {
+	captures Captures
+}
+
+// variable returns the name of the variable the pattern node stands for, if any.
+func variable(node ast.Node, prefix string) (string, bool) {
+	if stmt, ok := node.(*ast.ExprStmt); ok {
+		node = stmt.X
+	}
+	id, ok := node.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	return strings.CutPrefix(id.Name, prefix)
+}
+
+func (m *matcher) bind(name string, nodes ...ast.Node) bool {
+	if name == anyName {
+		return true
+	}
+	bound, ok := m.captures[name]
+	if !ok {
+		m.captures[name] = nodes
+		return true
+	}
+	if len(bound) != len(nodes) {
+		return false
+	}
+	for i := range nodes {
+		if !(&matcher{captures: Captures{}}).matchNode(bound[i], nodes[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *matcher) matchNode(pattern, node ast.Node) bool {
+	return m.matchValue(reflect.ValueOf(pattern), reflect.ValueOf(node))
+}
+
+// bindVariable binds the variable to the node, if the node is of the same kind, expression or statement,
+// as the pattern node standing for the variable.
+func (m *matcher) bindVariable(name string, pattern, node ast.Node) bool {
+	switch pattern.(type) {
+	case ast.Stmt:
+		if _, ok := node.(ast.Stmt); !ok {
+			return false
+		}
+	case ast.Expr:
+		if _, ok := node.(ast.Expr); !ok {
+			return false
+		}
+	}
+	return m.bind(name, node)
+}
+
+var (
+	nodeType         = reflect.TypeFor[ast.Node]()
+	posType          = reflect.TypeFor[token.Pos]()
+	objectType       = reflect.TypeFor[*ast.Object]()
+	scopeType        = reflect.TypeFor[*ast.Scope]()
+	commentGroupType = reflect.TypeFor[*ast.CommentGroup]()
+)
+
+func (m *matcher) matchValue(p, n reflect.Value) bool {
+	if p.Kind() == reflect.Interface {
+		if p.IsNil() || n.IsNil() {
+			return p.IsNil() && n.IsNil()
+		}
+		p, n = p.Elem(), n.Elem()
+	}
+	if p.Kind() == reflect.Pointer && !p.IsNil() && p.Type().Implements(nodeType) {
+		pattern := p.Interface().(ast.Node)
+		if name, ok := variable(pattern, varPrefix); ok {
+			node, isNode := n.Interface().(ast.Node)
+			return isNode && !n.IsNil() && m.bindVariable(name, pattern, node)
+		}
+	}
+	if p.Type() != n.Type() {
+		return false
+	}
+
+	switch p.Kind() {
+	case reflect.Pointer:
+		if p.IsNil() || n.IsNil() {
+			return p.IsNil() && n.IsNil()
+		}
+		return m.matchValue(p.Elem(), n.Elem())
+	case reflect.Struct:
+		for i := range p.NumField() {
+			switch p.Type().Field(i).Type {
+			case objectType, scopeType, commentGroupType:
+				continue
+			case posType:
+				// Only the presence of optional tokens, such as an ellipsis, matters.
+				if token.Pos(p.Field(i).Int()).IsValid() != token.Pos(n.Field(i).Int()).IsValid() {
+					return false
+				}
+				continue
+			}
+			if !m.matchValue(p.Field(i), n.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice:
+		ps := make([]reflect.Value, p.Len())
+		for i := range ps {
+			ps[i] = p.Index(i)
+		}
+		ns := make([]reflect.Value, n.Len())
+		for i := range ns {
+			ns[i] = n.Index(i)
+		}
+		return m.matchList(ps, ns)
+	default:
+		return p.Interface() == n.Interface()
+	}
+}
+
+// matchList matches lists of nodes, where $*name variables match any number of consecutive nodes.
+func (m *matcher) matchList(ps, ns []reflect.Value) bool {
+	if len(ps) == 0 {
+		return len(ns) == 0
+	}
+
+	if name, ok := listVariable(ps[0]); ok {
+		for i := range len(ns) + 1 {
+			saved := maps.Clone(m.captures)
+			if m.bind(name, nodes(ns[:i])...) && m.matchList(ps[1:], ns[i:]) {
+				return true
+			}
+			m.captures = saved
+		}
+		return false
+	}
+
+	if len(ns) == 0 {
+		return false
+	}
+	saved := maps.Clone(m.captures)
+	if m.matchValue(ps[0], ns[0]) && m.matchList(ps[1:], ns[1:]) {
+		return true
+	}
+	m.captures = saved
+	return false
+}
+
+func listVariable(v reflect.Value) (string, bool) {
+	node, ok := v.Interface().(ast.Node)
+	if !ok || reflect.ValueOf(node).IsNil() {
+		return "", false
+	}
+	return variable(node, listPrefix)
+}
+
+func nodes(values []reflect.Value) []ast.Node {
+	result := make([]ast.Node, 0, len(values))
+	for _, v := range values {
+		if node, ok := v.Interface().(ast.Node); ok {
+			result = append(result, node)
+		}
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package astpattern
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPattern_Match(t *testing.T) {
+	const src = `package p
+
+func f() {
+	fmt.Sprintf("%s", name)
+	fmt.Sprintf("%d", 1)
+	fmt.Sprintf("%s-%s", a, b)
+	fmt.Sprintf("%s", x...)
+	fmt.Println()
+	fmt.Println(a, b, c)
+	a = a + 1
+	a = b + 1
+	if err != nil {
+		return err
+	}
+}
+`
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{`fmt.Sprintf("%s", $x)`, []string{`fmt.Sprintf("%s", name) x=name`}},
+		{`fmt.Sprintf($_, $*args)`, []string{
+			`fmt.Sprintf("%s", name) args=name`,
+			`fmt.Sprintf("%d", 1) args=1`,
+			`fmt.Sprintf("%s-%s", a, b) args=a,b`,
+		}},
+		{`fmt.Sprintf("%s", $x...)`, []string{`fmt.Sprintf("%s", x...) x=x`}},
+		{`fmt.Println($*_)`, []string{`fmt.Println()`, `fmt.Println(a, b, c)`}},
+		{`fmt.Println($*_, $last)`, []string{`fmt.Println(a, b, c) last=c`}},
+		{`$x = $x + 1`, []string{`a = a + 1 x=a`}},
+		{`if $err != nil { return $err }`, []string{"if err != nil {\n\treturn err\n} err=err"}},
+		{`if $cond { $*_ }`, []string{"if err != nil {\n\treturn err\n} cond=err != nil"}},
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	render := func(node ast.Node) string {
+		var buf bytes.Buffer
+		if err := format.Node(&buf, fset, node); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			p, err := Compile(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			ast.Inspect(file, func(node ast.Node) bool {
+				captures, ok := p.Match(node)
+				if !ok {
+					return true
+				}
+				match := render(node)
+				for _, name := range p.Variables {
+					var rendered []string
+					for _, captured := range captures[name] {
+						rendered = append(rendered, render(captured))
+					}
+					match += " " + name + "=" + strings.Join(rendered, ",")
+				}
+				got = append(got, match)
+				return true
+			})
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got matches %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	p, err := Compile(`$f($x, $*rest, $x)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"f", "x", "rest"}; !slices.Equal(p.Variables, want) {
+		t.Errorf("got variables %v, want %v", p.Variables, want)
+	}
+
+	for _, pattern := range []string{`fmt.Sprintf(`, `a := 1; b := 2`} {
+		if _, err := Compile(pattern); err == nil {
+			t.Errorf("expected an error compiling %q", pattern)
+		}
+	}
+}
@@ -2,5 +2,7 @@
 // presently used by the following rules:
 //   - early-return
 //   - indent-error-flow
+//   - mutex-usage
 //   - superfluous-else
+//   - unclosed-resource
 package ifelse
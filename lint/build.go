@@ -0,0 +1,53 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"go/build"
+	"io"
+	"path/filepath"
+)
+
+// buildContexts returns the build contexts whose constraints the linted files must satisfy,
+// one per configured combination of GOOS and GOARCH, or nil if build constraints are not configured.
+func buildContexts(config Config) []*build.Context {
+	if len(config.GOOS) == 0 && len(config.GOARCH) == 0 && len(config.BuildTags) == 0 {
+		return nil
+	}
+
+	goos := config.GOOS
+	if len(goos) == 0 {
+		goos = []string{build.Default.GOOS}
+	}
+	goarch := config.GOARCH
+	if len(goarch) == 0 {
+		goarch = []string{build.Default.GOARCH}
+	}
+
+	var result []*build.Context
+	for _, targetOS := range goos {
+		for _, targetArch := range goarch {
+			ctx := build.Default
+			ctx.GOOS = targetOS
+			ctx.GOARCH = targetArch
+			ctx.BuildTags = config.BuildTags
+			result = append(result, &ctx)
+		}
+	}
+	return result
+}
+
+// matchBuildContext reports whether the file with the given content satisfies the build constraints
+// of the context, both from its name (i.e. a _linux.go suffix) and from its //go:build line.
+func matchBuildContext(ctx *build.Context, filename string, content []byte) (bool, error) {
+	matcher := *ctx
+	matcher.OpenFile = func(string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+
+	match, err := matcher.MatchFile(filepath.Dir(filename), filepath.Base(filename))
+	if err != nil {
+		return false, fmt.Errorf("checking the build constraints of %s: %w", filename, err)
+	}
+	return match, nil
+}
@@ -0,0 +1,123 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// packageFilesRule reports each linted file with the number of files of its package,
+// and whether it got an error type checking the package.
+type packageFilesRule struct{}
+
+func (*packageFilesRule) Name() string { return "package-files" }
+
+func (*packageFilesRule) Apply(file *File, _ Arguments) []Failure {
+	failure := Failure{Confidence: 1, Failure: fmt.Sprintf("%d files", len(file.Pkg.Files()))}
+	if err := file.Pkg.TypeCheck(); err != nil {
+		failure.Failure += ", type error"
+	}
+	failure.Position.Start.Filename = file.Name
+	failure.Position.Start.Line = 1
+	return []Failure{failure}
+}
+
+func TestLinter_buildConstraints(t *testing.T) {
+	sources := map[string]string{
+		"pkg/common.go":      "package pkg\n\nfunc common() { platform() }\n",
+		"pkg/pkg_linux.go":   "package pkg\n\nfunc platform() {}\n",
+		"pkg/pkg_windows.go": "package pkg\n\nfunc platform() {}\n",
+		"pkg/integration.go": "//go:build integration\n\npackage pkg\n",
+		"pkg/arm64.go":       "//go:build arm64\n\npackage pkg\n",
+	}
+	reader := func(path string) ([]byte, error) {
+		src, ok := sources[path]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return []byte(src), nil
+	}
+	packages := [][]string{{"pkg/arm64.go", "pkg/common.go", "pkg/integration.go", "pkg/pkg_linux.go", "pkg/pkg_windows.go"}}
+
+	tests := map[string]struct {
+		goos, goarch, tags []string
+		want               []string
+		wantTypeErrors     int
+	}{
+		"no build constraints": {
+			want: []string{
+				"pkg/arm64.go: 5 files",
+				"pkg/common.go: 5 files",
+				"pkg/integration.go: 5 files",
+				"pkg/pkg_linux.go: 5 files",
+				"pkg/pkg_windows.go: 5 files",
+			},
+			wantTypeErrors: 1,
+		},
+		"linux": {
+			goos: []string{"linux"}, goarch: []string{"amd64"},
+			want: []string{
+				"pkg/common.go: 2 files",
+				"pkg/pkg_linux.go: 2 files",
+			},
+		},
+		"windows with tags": {
+			goos: []string{"windows"}, goarch: []string{"amd64"}, tags: []string{"integration"},
+			want: []string{
+				"pkg/common.go: 3 files",
+				"pkg/integration.go: 3 files",
+				"pkg/pkg_windows.go: 3 files",
+			},
+		},
+		"several platforms": {
+			goos: []string{"linux", "windows"}, goarch: []string{"amd64", "arm64"},
+			want: []string{
+				// failures found on several platforms are reported once:
+				// common.go is linted with 2 files on amd64, and with 3 files on arm64
+				"pkg/arm64.go: 3 files",
+				"pkg/common.go: 2 files",
+				"pkg/common.go: 3 files",
+				"pkg/pkg_linux.go: 2 files",
+				"pkg/pkg_linux.go: 3 files",
+				"pkg/pkg_windows.go: 2 files",
+				"pkg/pkg_windows.go: 3 files",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := Config{
+				GoVersion: defaultGoVersion,
+				GOOS:      tc.goos,
+				GOARCH:    tc.goarch,
+				BuildTags: tc.tags,
+			}
+			l := New(reader, 0)
+			failures, err := l.Lint(packages, []Rule{&packageFilesRule{}}, config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			typeErrors := 0
+			for failure := range failures {
+				msg, typeError := strings.CutSuffix(failure.Failure, ", type error")
+				if typeError {
+					typeErrors++
+				}
+				got = append(got, failure.Filename()+": "+msg)
+			}
+			slices.Sort(got)
+
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got failures %q, want %q", got, tc.want)
+			}
+			if typeErrors != tc.wantTypeErrors {
+				t.Errorf("got %d type errors, want %d", typeErrors, tc.wantTypeErrors)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package lint
 
 import (
+	"time"
+
 	goversion "github.com/hashicorp/go-version"
 )
 
@@ -69,4 +71,22 @@ type Config struct {
 	// If set, overrides the go language version specified in go.mod of
 	// packages being linted, and assumes this specific language version.
 	GoVersion *goversion.Version `toml:"go-version"`
+	// RuleTimeout is the time budget of a rule on a single file.
+	// Rules exceeding it are reported as internal failures. Zero means no limit.
+	RuleTimeout time.Duration `toml:"rule-timeout"`
+	// GOOS, GOARCH and BuildTags restrict the linted files to those satisfying the build constraints
+	// for these operating systems, architectures and tags. When several operating systems or architectures
+	// are set, each combination is linted in turn, and failures found in several combinations are reported once.
+	// Build constraints are ignored when none of them is set.
+	GOOS      []string `toml:"goos"`
+	GOARCH    []string `toml:"goarch"`
+	BuildTags []string `toml:"build-tags"`
+	// External are the rules implemented by external commands, keyed by rule name.
+	// They are configured like the other rules, in the Rules section.
+	External ExternalRulesConfig `toml:"external"`
+	// Patterns are the rules reporting the code matching syntax patterns, keyed by rule name.
+	Patterns PatternRulesConfig `toml:"pattern"`
+	// OverlappingRules maps a rule to the rules whose findings it also reports: a failure of one of them
+	// overlapping a failure of the rule in the same file is collapsed into the latter.
+	OverlappingRules map[string][]string `toml:"overlapping-rules"`
 }
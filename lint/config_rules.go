@@ -0,0 +1,28 @@
+package lint
+
+// ExternalRuleConfig is type used for the configuration of a rule implemented by an external command.
+type ExternalRuleConfig struct {
+	// Command is the command line of the process implementing the rule.
+	Command []string
+}
+
+// ExternalRulesConfig defines the config for all external rules.
+type ExternalRulesConfig = map[string]ExternalRuleConfig
+
+// PatternRuleConfig is type used for the configuration of a rule reporting the code matching a syntax pattern.
+type PatternRuleConfig struct {
+	// Pattern is a Go expression or statement, where $name matches any expression
+	// and $*name any number of elements of a list.
+	Pattern string
+	// Where maps variables of the pattern to the type the code they match must have.
+	Where map[string]string
+	// Message is the failure message, where variables are replaced by the code they match.
+	Message string
+	// Severity is the severity of the rule, unless set in the Rules section.
+	Severity Severity
+	// Replacement is the code suggested instead of the matching code, where variables are replaced by the code they match.
+	Replacement string
+}
+
+// PatternRulesConfig defines the config for all pattern rules.
+type PatternRulesConfig = map[string]PatternRuleConfig
@@ -0,0 +1,72 @@
+package lint
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+// fileStatus is what the linter did with a file.
+type fileStatus int
+
+const (
+	fileLinted fileStatus = iota
+	fileGenerated
+	fileInvalid
+)
+
+// Coverage collects the files linted, and those skipped.
+//
+// A nil *Coverage is valid and collects nothing.
+type Coverage struct {
+	mu    sync.Mutex
+	files map[string]fileStatus
+}
+
+// NewCoverage creates an empty coverage.
+func NewCoverage() *Coverage {
+	return &Coverage{files: map[string]fileStatus{}}
+}
+
+func (c *Coverage) add(filename string, status fileStatus) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.files[filename] = status
+}
+
+// Linted returns the sorted names of the files the rules were applied to.
+func (c *Coverage) Linted() []string {
+	return c.filesWith(fileLinted)
+}
+
+// Generated returns the sorted names of the files skipped because they are generated.
+func (c *Coverage) Generated() []string {
+	return c.filesWith(fileGenerated)
+}
+
+// Invalid returns the sorted names of the files skipped because they cannot be parsed.
+func (c *Coverage) Invalid() []string {
+	return c.filesWith(fileInvalid)
+}
+
+func (c *Coverage) filesWith(status fileStatus) []string {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var result []string
+	for _, filename := range slices.Sorted(maps.Keys(c.files)) {
+		if c.files[filename] == status {
+			result = append(result, filename)
+		}
+	}
+	return result
+}
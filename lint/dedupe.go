@@ -0,0 +1,48 @@
+package lint
+
+import "go/token"
+
+// failureKey identifies the failures reported several times, i.e. for a file linted in several build contexts
+// or in several packages.
+type failureKey struct {
+	filename   string
+	start, end token.Position
+	rule       string
+	message    string
+}
+
+func newFailureKey(failure Failure) failureKey {
+	start, end := failure.Position.Start, failure.Position.End
+	// The offsets depend on the content seen by the linter, the lines and columns are enough.
+	start.Offset, end.Offset = 0, 0
+	return failureKey{
+		filename: failure.Filename(),
+		start:    start,
+		end:      end,
+		rule:     failure.RuleName,
+		message:  failure.Failure,
+	}
+}
+
+// DedupeFailures forwards the failures, except those identical to a failure already forwarded,
+// i.e. with the same file, range, rule and message.
+// Internal failures are always forwarded.
+func DedupeFailures(failures <-chan Failure) <-chan Failure {
+	result := make(chan Failure)
+	go func() {
+		defer close(result)
+
+		seen := map[failureKey]bool{}
+		for failure := range failures {
+			if !failure.IsInternal() {
+				key := newFailureKey(failure)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+			result <- failure
+		}
+	}()
+	return result
+}
@@ -0,0 +1,37 @@
+package lint
+
+import (
+	"go/token"
+	"slices"
+	"testing"
+)
+
+func TestDedupeFailures(t *testing.T) {
+	failure := func(offset int, message string) Failure {
+		return Failure{
+			Failure:  message,
+			RuleName: "rule",
+			Position: FailurePosition{
+				Start: token.Position{Filename: "a.go", Offset: offset, Line: 2, Column: 1},
+				End:   token.Position{Filename: "a.go", Offset: offset + 4, Line: 2, Column: 5},
+			},
+		}
+	}
+	failures := make(chan Failure, 5)
+	failures <- failure(10, "first")
+	failures <- failure(12, "first") // same lines and columns, other content
+	failures <- failure(10, "second")
+	failures <- NewInternalFailure("internal")
+	failures <- NewInternalFailure("internal")
+	close(failures)
+
+	var got []string
+	for f := range DedupeFailures(failures) {
+		got = append(got, f.Failure)
+	}
+
+	want := []string{"first", "second", "internal", "internal"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got failures %q, want %q", got, want)
+	}
+}
@@ -1,6 +1,7 @@
 package lint
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
 )
@@ -47,6 +48,8 @@ const (
 
 	// failureCategoryInternal indicates internal failures.
 	failureCategoryInternal FailureCategory = "REVIVE_INTERNAL"
+	// failureCategoryRuleCrash indicates internal failures caused by a rule panicking.
+	failureCategoryRuleCrash FailureCategory = "REVIVE_RULE_CRASH"
 	// failureCategoryValidity indicates validity issues.
 	failureCategoryValidity FailureCategory = "validity"
 )
@@ -95,7 +98,12 @@ func (f *Failure) Filename() string {
 
 // IsInternal returns true if this failure is internal, false otherwise.
 func (f *Failure) IsInternal() bool {
-	return f.Category == failureCategoryInternal
+	return f.Category == failureCategoryInternal || f.IsRuleCrash()
+}
+
+// IsRuleCrash returns true if this failure reports a rule that panicked, false otherwise.
+func (f *Failure) IsRuleCrash() bool {
+	return f.Category == failureCategoryRuleCrash
 }
 
 // NewInternalFailure yields an internal failure with the given message as failure message.
@@ -105,3 +113,15 @@ func NewInternalFailure(message string) Failure {
 		Failure:  message,
 	}
 }
+
+// NewRuleCrashFailure yields an internal failure reporting that the given rule panicked on the given file.
+func NewRuleCrashFailure(ruleName, filename string, panicValue any) Failure {
+	return Failure{
+		Category: failureCategoryRuleCrash,
+		RuleName: ruleName,
+		Failure:  fmt.Sprintf("rule %s panicked on file %s: %v", ruleName, filename, panicValue),
+		Position: FailurePosition{
+			Start: token.Position{Filename: filename},
+		},
+	}
+}
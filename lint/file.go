@@ -2,6 +2,8 @@ package lint
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/printer"
@@ -10,7 +12,12 @@ import (
 	"log/slog"
 	"math"
 	"regexp"
+	"runtime/debug"
 	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/go/ast/inspector"
 )
 
 // File abstraction used for representing files.
@@ -20,11 +27,26 @@ type File struct {
 	content []byte
 	AST     *ast.File
 	logger  *slog.Logger
+
+	inspectorOnce sync.Once
+	inspector     *inspector.Inspector
 }
 
 // IsTest returns if the file contains tests.
 func (f *File) IsTest() bool { return strings.HasSuffix(f.Name, "_test.go") }
 
+// Variant returns the variant of the package the file is compiled in.
+func (f *File) Variant() PackageVariant {
+	switch {
+	case !f.IsTest():
+		return LibraryVariant
+	case strings.HasSuffix(f.AST.Name.Name, "_test"):
+		return ExternalTestVariant
+	default:
+		return TestVariant
+	}
+}
+
 // IsImportable returns if the symbols defined in this file can be imported in other packages.
 //
 // Symbols from the package `main` or test files are not exported, so they cannot be imported.
@@ -42,6 +64,14 @@ func (f *File) IsImportable() bool {
 	return true
 }
 
+// Logger returns the logger of the linter, for rules to log problems other than failures.
+func (f *File) Logger() *slog.Logger {
+	if f.logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return f.logger
+}
+
 // Content returns the file's content.
 func (f *File) Content() []byte {
 	return f.content
@@ -62,6 +92,15 @@ func NewFile(name string, content []byte, pkg *Package) (*File, error) {
 	}, nil
 }
 
+// Inspector returns the inspector of the file AST, built on first use.
+// It traverses the AST faster than [ast.Inspect], especially when looking only for some types of nodes.
+func (f *File) Inspector() *inspector.Inspector {
+	f.inspectorOnce.Do(func() {
+		f.inspector = inspector.New([]*ast.File{f.AST})
+	})
+	return f.inspector
+}
+
 // ToPosition returns line and column for given position.
 func (f *File) ToPosition(pos token.Pos) token.Position {
 	return f.Pkg.fset.Position(pos)
@@ -119,48 +158,158 @@ const (
 	directiveSpecifyDisableRule   = "specify-disable-rule"
 )
 
-func (f *File) lint(rules []Rule, config Config, failures chan Failure) error {
+func (f *File) lint(ctx context.Context, rules []Rule, config Config, failures chan Failure) error {
 	rulesConfig := config.Rules
 	_, mustSpecifyDisableReason := config.Directives[directiveSpecifyDisableReason]
 	_, mustSpecifyDisableRules := config.Directives[directiveSpecifyDisableRule]
 	disabledIntervals := f.disabledIntervals(rules, mustSpecifyDisableReason, mustSpecifyDisableRules, failures)
+	var nodeRules []NodeRule
 	for _, currentRule := range rules {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		ruleConfig := rulesConfig[currentRule.Name()]
 		if ruleConfig.MustExclude(f.Name) {
 			continue
 		}
-		currentFailures := currentRule.Apply(f, ruleConfig.Arguments)
-		filtered := currentFailures[:0]
-		for _, failure := range currentFailures {
-			// Log and skip internal failures: they signal a rule could not run on this file,
-			// but other rules can still produce useful reports.
-			if failure.IsInternal() {
-				f.logger.Warn("rule skipped due to internal failure",
-					"rule", currentRule.Name(),
-					"file", f.Name,
-					"failure", failure.Failure,
-				)
-				continue
-			}
-
-			if failure.RuleName == "" {
-				failure.RuleName = currentRule.Name()
-			}
-			if failure.Node != nil {
-				failure.Position = ToFailurePosition(failure.Node.Pos(), failure.Node.End(), f)
-			}
-			filtered = append(filtered, failure)
+		// The traversal shared by node rules cannot be interrupted for a single rule running out of time.
+		if nodeRule, ok := currentRule.(NodeRule); ok && config.RuleTimeout <= 0 {
+			nodeRules = append(nodeRules, nodeRule)
+			continue
 		}
-		currentFailures = f.filterFailures(filtered, disabledIntervals)
-		for _, failure := range currentFailures {
-			if failure.Confidence >= config.Confidence {
-				failures <- failure
-			}
+		start := time.Now()
+		currentFailures, completed := f.applyRule(ctx, currentRule, ruleConfig.Arguments, config.RuleTimeout, failures)
+		f.Pkg.profile.addRule(currentRule.Name(), time.Since(start))
+		if !completed {
+			continue
 		}
+		f.report(currentRule, currentFailures, disabledIntervals, config.Confidence, failures)
+	}
+
+	if len(nodeRules) == 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, result := range f.inspectNodeRules(nodeRules, rulesConfig, failures) {
+		f.report(result.rule, result.failures, disabledIntervals, config.Confidence, failures)
 	}
 	return nil
 }
 
+// report sends the failures found by the rule, except internal ones,
+// those in intervals where the rule is disabled and those below the confidence threshold.
+func (f *File) report(rule Rule, ruleFailures []Failure, disabledIntervals disabledIntervalsMap, confidence float64, failures chan Failure) {
+	filtered := ruleFailures[:0]
+	for _, failure := range ruleFailures {
+		// Log and skip internal failures: they signal a rule could not run on this file,
+		// but other rules can still produce useful reports.
+		if failure.IsInternal() {
+			f.logger.Warn("rule skipped due to internal failure",
+				"rule", rule.Name(),
+				"file", f.Name,
+				"failure", failure.Failure,
+			)
+			continue
+		}
+
+		if failure.RuleName == "" {
+			failure.RuleName = rule.Name()
+		}
+		if failure.Node != nil {
+			failure.Position = ToFailurePosition(failure.Node.Pos(), failure.Node.End(), f)
+		}
+		filtered = append(filtered, failure)
+	}
+	for _, failure := range f.filterFailures(filtered, disabledIntervals) {
+		if failure.Confidence >= confidence {
+			failures <- failure
+		}
+	}
+}
+
+// applyRule applies the rule to the file, within the given time budget if it is positive.
+// It reports whether the rule completed: when it panics or exceeds its time budget,
+// the problem is logged and sent as an internal failure, and its result, if any, is discarded.
+func (f *File) applyRule(ctx context.Context, rule Rule, arguments Arguments, timeout time.Duration, failures chan Failure) (result []Failure, completed bool) {
+	type outcome struct {
+		result []Failure
+		crash  *Failure
+	}
+	apply := func() outcome {
+		result, crash := f.safeApply(rule, arguments)
+		return outcome{result, crash}
+	}
+
+	var o outcome
+	if timeout <= 0 {
+		o = apply()
+	} else {
+		done := make(chan outcome, 1)
+		go func() {
+			done <- apply()
+		}()
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		select {
+		case o = <-done:
+		case <-ctx.Done():
+			return nil, false
+		case <-timer.C:
+			f.logger.Warn("rule skipped due to time budget overrun",
+				"rule", rule.Name(),
+				"file", f.Name,
+				"timeout", timeout,
+			)
+			failure := NewInternalFailure(fmt.Sprintf("rule %s exceeded its time budget of %v on file %s", rule.Name(), timeout, f.Name))
+			failure.RuleName = rule.Name()
+			failure.Position.Start.Filename = f.Name
+			failures <- failure
+			return nil, false
+		}
+	}
+
+	if o.crash != nil {
+		failures <- *o.crash
+		return nil, false
+	}
+	return o.result, true
+}
+
+// safeApply applies the rule to the file, recovering from a panic of the rule.
+// In that case, it logs the panic, with its stack trace at debug level,
+// and returns a rule crash failure naming the rule and the file.
+func (f *File) safeApply(rule Rule, arguments Arguments) (result []Failure, crash *Failure) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, crash = nil, f.ruleCrash(rule, r)
+		}
+	}()
+
+	return rule.Apply(f, arguments), nil
+}
+
+// ruleCrash logs the panic of the rule, with its stack trace at debug level,
+// and returns a rule crash failure naming the rule and the file.
+// It must be called by the deferred function recovering from the panic.
+func (f *File) ruleCrash(rule Rule, panicValue any) *Failure {
+	f.logger.Error("rule panicked",
+		"rule", rule.Name(),
+		"file", f.Name,
+		"panic", panicValue,
+	)
+	f.logger.Debug("rule panic stack trace",
+		"rule", rule.Name(),
+		"file", f.Name,
+		"stack", string(debug.Stack()),
+	)
+	failure := NewRuleCrashFailure(rule.Name(), f.Name, panicValue)
+	return &failure
+}
+
 type enableDisableConfig struct {
 	enabled  bool
 	position int
@@ -2,12 +2,15 @@ package lint
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"go/ast"
 	"go/token"
 	"log/slog"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 )
 
 type fakeRule struct {
@@ -59,7 +62,7 @@ func TestFile_lint_internalFailureDoesNotAbortOtherRules(t *testing.T) {
 	}
 
 	failures := make(chan Failure, 4)
-	if err := f.lint(rules, cfg, failures); err != nil {
+	if err := f.lint(context.Background(), rules, cfg, failures); err != nil {
 		t.Fatal("unexpected error from linting:", err)
 	}
 	close(failures)
@@ -90,6 +93,193 @@ func TestFile_lint_internalFailureDoesNotAbortOtherRules(t *testing.T) {
 	}
 }
 
+type slowRule struct {
+	name    string
+	release chan struct{}
+}
+
+var _ Rule = (*slowRule)(nil)
+
+func (r *slowRule) Name() string { return r.name }
+
+func (r *slowRule) Apply(*File, Arguments) []Failure {
+	<-r.release
+	return []Failure{{Confidence: 1, Failure: "must not reach the channel"}}
+}
+
+func TestFile_lint_ruleTimeout(t *testing.T) {
+	slow := &slowRule{name: "slow-rule", release: make(chan struct{})}
+	defer close(slow.release)
+	rules := []Rule{
+		slow,
+		&fakeRule{
+			name: "normal-rule",
+			failures: []Failure{
+				{
+					Confidence: 1,
+					Failure:    "must reach the channel",
+				},
+			},
+		},
+	}
+
+	cfg := Config{
+		Confidence:  0.8,
+		RuleTimeout: 10 * time.Millisecond,
+		Rules: RulesConfig{
+			"slow-rule":   {},
+			"normal-rule": {},
+		},
+	}
+
+	var logBuf bytes.Buffer
+	f := &File{
+		Name:   "test.go",
+		Pkg:    &Package{fset: token.NewFileSet()},
+		AST:    &ast.File{},
+		logger: slog.New(slog.NewTextHandler(&logBuf, &slog.HandlerOptions{Level: slog.LevelWarn})),
+	}
+
+	failures := make(chan Failure, 4)
+	if err := f.lint(context.Background(), rules, cfg, failures); err != nil {
+		t.Fatal("unexpected error from linting:", err)
+	}
+	close(failures)
+
+	var got []Failure
+	for failure := range failures {
+		got = append(got, failure)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected exactly 2 failures to be reported, got %d: %+v", len(got), got)
+	}
+	if !got[0].IsInternal() || got[0].RuleName != "slow-rule" || got[0].Filename() != "test.go" {
+		t.Errorf("expected an internal failure for %q on %q, got %+v", "slow-rule", "test.go", got[0])
+	}
+	if got[1].RuleName != "normal-rule" {
+		t.Errorf("expected failure from %q, got %q", "normal-rule", got[1].RuleName)
+	}
+
+	logged := logBuf.String()
+	for _, want := range []string{
+		`msg="rule skipped due to time budget overrun"`,
+		"rule=slow-rule",
+		"file=test.go",
+		"timeout=10ms",
+	} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("expected log output to contain %q, got %q", want, logged)
+		}
+	}
+}
+
+func TestFile_lint_cancelled(t *testing.T) {
+	rules := []Rule{
+		&fakeRule{
+			name:     "normal-rule",
+			failures: []Failure{{Confidence: 1, Failure: "must not reach the channel"}},
+		},
+	}
+	f := &File{
+		Name:   "test.go",
+		Pkg:    &Package{fset: token.NewFileSet()},
+		AST:    &ast.File{},
+		logger: slog.New(slog.DiscardHandler),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	failures := make(chan Failure, 4)
+	err := f.lint(ctx, rules, Config{Confidence: 0.8}, failures)
+	close(failures)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected error %v, got %v", context.Canceled, err)
+	}
+	if len(failures) != 0 {
+		t.Errorf("expected no failure to be reported, got %d", len(failures))
+	}
+}
+
+type panickingRule struct{}
+
+var _ Rule = (*panickingRule)(nil)
+
+func (*panickingRule) Name() string { return "panicking-rule" }
+
+func (*panickingRule) Apply(*File, Arguments) []Failure {
+	panic("boom")
+}
+
+func TestFile_lint_rulePanic(t *testing.T) {
+	for name, timeout := range map[string]time.Duration{
+		"without time budget": 0,
+		"with time budget":    time.Minute,
+	} {
+		t.Run(name, func(t *testing.T) {
+			rules := []Rule{
+				&panickingRule{},
+				&fakeRule{
+					name:     "normal-rule",
+					failures: []Failure{{Confidence: 1, Failure: "must reach the channel"}},
+				},
+			}
+			cfg := Config{
+				Confidence:  0.8,
+				RuleTimeout: timeout,
+				Rules: RulesConfig{
+					"panicking-rule": {},
+					"normal-rule":    {},
+				},
+			}
+
+			var logBuf bytes.Buffer
+			f := &File{
+				Name:   "test.go",
+				Pkg:    &Package{fset: token.NewFileSet()},
+				AST:    &ast.File{},
+				logger: slog.New(slog.NewTextHandler(&logBuf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+			}
+
+			failures := make(chan Failure, 4)
+			if err := f.lint(context.Background(), rules, cfg, failures); err != nil {
+				t.Fatal("unexpected error from linting:", err)
+			}
+			close(failures)
+
+			var got []Failure
+			for failure := range failures {
+				got = append(got, failure)
+			}
+
+			if len(got) != 2 {
+				t.Fatalf("expected exactly 2 failures to be reported, got %d: %+v", len(got), got)
+			}
+			if !got[0].IsRuleCrash() || !got[0].IsInternal() || got[0].RuleName != "panicking-rule" || got[0].Filename() != "test.go" {
+				t.Errorf("expected a rule crash failure for %q on %q, got %+v", "panicking-rule", "test.go", got[0])
+			}
+			if want := "rule panicking-rule panicked on file test.go: boom"; got[0].Failure != want {
+				t.Errorf("expected failure message %q, got %q", want, got[0].Failure)
+			}
+			if got[1].RuleName != "normal-rule" {
+				t.Errorf("expected failure from %q, got %q", "normal-rule", got[1].RuleName)
+			}
+
+			logged := logBuf.String()
+			for _, want := range []string{
+				`level=ERROR msg="rule panicked" rule=panicking-rule file=test.go panic=boom`,
+				`level=DEBUG msg="rule panic stack trace"`,
+				"panickingRule",
+			} {
+				if !strings.Contains(logged, want) {
+					t.Errorf("expected log output to contain %q, got %q", want, logged)
+				}
+			}
+		})
+	}
+}
+
 func TestFile_disabledIntervals(t *testing.T) {
 	buildCommentGroups := func(comments ...string) []*ast.CommentGroup {
 		commentGroups := make([]*ast.CommentGroup, 0, len(comments))
@@ -1,5 +1,7 @@
 package lint
 
+import "io"
+
 // FormatterMetadata configuration of a formatter.
 type FormatterMetadata struct {
 	Name        string
@@ -12,3 +14,27 @@ type Formatter interface {
 	Format(<-chan Failure, Config) (string, error)
 	Name() string
 }
+
+// FormatterArguments is type used for the arguments of a formatter.
+type FormatterArguments = map[string]string
+
+// ConfigurableFormatter defines an abstract configurable formatter interface.
+type ConfigurableFormatter interface {
+	Configure(FormatterArguments) error
+}
+
+// StreamingFormatter defines an interface for formatters that write each failure
+// to an [io.Writer] as soon as it is received, instead of returning the whole report at once.
+type StreamingFormatter interface {
+	Formatter
+	FormatTo(io.Writer, <-chan Failure, Config) error
+}
+
+// ExitCodeFormatter defines an interface for formatters that decide the exit code of the run,
+// for instance by comparing the failures with those of a previous run.
+type ExitCodeFormatter interface {
+	// ExitCode is called after Format with the exit code computed from the severity of the failures,
+	// and returns the exit code to use instead. When the failures are formatted to several outputs,
+	// the returned exit code is only used if it is greater than the computed one.
+	ExitCode(computed int) int
+}
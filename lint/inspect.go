@@ -0,0 +1,107 @@
+package lint
+
+import (
+	"go/ast"
+	"reflect"
+	"time"
+)
+
+// nodeRuleResult is the result of a node rule on a file.
+type nodeRuleResult struct {
+	rule     NodeRule
+	check    func(ast.Node)
+	failures []Failure
+	crashed  bool
+	duration time.Duration
+}
+
+// inspectNodeRules applies the node rules to the file in a single traversal of its AST,
+// dispatching each node to the rules checking its type. A rule panicking is sent as a rule crash failure,
+// and is left out of the rest of the traversal and of the results.
+func (f *File) inspectNodeRules(rules []NodeRule, rulesConfig RulesConfig, failures chan Failure) []*nodeRuleResult {
+	profiling := f.Pkg.profile != nil
+	var start time.Time
+
+	var results, allNodes []*nodeRuleResult
+	byType := map[reflect.Type][]*nodeRuleResult{}
+	var inspected []ast.Node
+	for _, rule := range rules {
+		result := &nodeRuleResult{rule: rule}
+		onFailure := func(failure Failure) {
+			result.failures = append(result.failures, failure)
+		}
+		if profiling {
+			start = time.Now()
+		}
+		f.safeCheck(result, func() {
+			result.check = rule.CheckNodes(f, rulesConfig[rule.Name()].Arguments, onFailure)
+		}, failures)
+		if profiling {
+			result.duration += time.Since(start)
+		}
+		if result.crashed || result.check == nil {
+			f.Pkg.profile.addRule(rule.Name(), result.duration)
+			continue
+		}
+		results = append(results, result)
+
+		nodeTypes := rule.NodeTypes()
+		if len(nodeTypes) == 0 {
+			allNodes = append(allNodes, result)
+			continue
+		}
+		for _, nodeType := range nodeTypes {
+			typ := reflect.TypeOf(nodeType)
+			if _, ok := byType[typ]; !ok {
+				inspected = append(inspected, nodeType)
+			}
+			byType[typ] = append(byType[typ], result)
+		}
+	}
+	if len(allNodes) > 0 {
+		inspected = nil
+	}
+
+	dispatch := func(results []*nodeRuleResult, node ast.Node) {
+		for _, result := range results {
+			if result.crashed {
+				continue
+			}
+			if profiling {
+				start = time.Now()
+			}
+			f.safeCheck(result, func() { result.check(node) }, failures)
+			if profiling {
+				result.duration += time.Since(start)
+			}
+		}
+	}
+	if len(results) > 0 {
+		f.Inspector().Preorder(inspected, func(node ast.Node) {
+			dispatch(byType[reflect.TypeOf(node)], node)
+			dispatch(allNodes, node)
+		})
+	}
+
+	completed := results[:0]
+	for _, result := range results {
+		f.Pkg.profile.addRule(result.rule.Name(), result.duration)
+		if !result.crashed {
+			completed = append(completed, result)
+		}
+	}
+	return completed
+}
+
+// safeCheck calls check for the rule of the result, recovering from a panic of the rule.
+// In that case, it sends a rule crash failure and marks the rule as crashed.
+func (f *File) safeCheck(result *nodeRuleResult, check func(), failures chan Failure) {
+	defer func() {
+		if r := recover(); r != nil {
+			result.crashed = true
+			failures <- *f.ruleCrash(result.rule, r)
+		}
+	}()
+
+	check()
+}
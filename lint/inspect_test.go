@@ -0,0 +1,116 @@
+package lint
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+)
+
+// callsRule reports the calls of the file, and counts the nodes it is given.
+type callsRule struct {
+	nodes int
+}
+
+var _ NodeRule = (*callsRule)(nil)
+
+func (*callsRule) Name() string { return "calls" }
+
+func (r *callsRule) Apply(file *File, arguments Arguments) []Failure {
+	return ApplyNodeRule(r, file, arguments)
+}
+
+func (*callsRule) NodeTypes() []ast.Node { return []ast.Node{(*ast.CallExpr)(nil)} }
+
+func (r *callsRule) CheckNodes(file *File, _ Arguments, onFailure func(Failure)) func(ast.Node) {
+	return func(node ast.Node) {
+		r.nodes++
+		onFailure(Failure{Confidence: 1, Node: node, Failure: "call of " + file.Render(node.(*ast.CallExpr).Fun)})
+	}
+}
+
+// panickingNodeRule panics on the second identifier of the file.
+type panickingNodeRule struct {
+	nodes int
+}
+
+func (*panickingNodeRule) Name() string { return "panicking-node-rule" }
+
+func (r *panickingNodeRule) Apply(file *File, arguments Arguments) []Failure {
+	return ApplyNodeRule(r, file, arguments)
+}
+
+func (*panickingNodeRule) NodeTypes() []ast.Node { return []ast.Node{(*ast.Ident)(nil)} }
+
+func (r *panickingNodeRule) CheckNodes(*File, Arguments, func(Failure)) func(ast.Node) {
+	return func(ast.Node) {
+		r.nodes++
+		if r.nodes == 2 {
+			panic("boom")
+		}
+	}
+}
+
+func TestFile_lint_nodeRules(t *testing.T) {
+	const src = `package p
+
+func f() {
+	g(h())
+}
+`
+	for name, timeout := range map[string]time.Duration{
+		"shared traversal": 0,
+		"with time budget": time.Minute,
+	} {
+		t.Run(name, func(t *testing.T) {
+			pkg := &Package{fset: token.NewFileSet(), profile: NewProfile()}
+			astFile, err := parser.ParseFile(pkg.fset, "p.go", src, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f := &File{Name: "p.go", Pkg: pkg, AST: astFile, logger: slog.New(slog.DiscardHandler)}
+
+			calls, panicking := &callsRule{}, &panickingNodeRule{}
+			cfg := Config{RuleTimeout: timeout, Rules: RulesConfig{"calls": {}, "panicking-node-rule": {}}}
+			failures := make(chan Failure, 4)
+			if err := f.lint(context.Background(), []Rule{panicking, calls}, cfg, failures); err != nil {
+				t.Fatal(err)
+			}
+			close(failures)
+
+			var got []string
+			for failure := range failures {
+				if failure.IsRuleCrash() {
+					got = append(got, failure.RuleName+" crashed")
+					continue
+				}
+				got = append(got, failure.Position.Start.String()+": "+failure.Failure)
+			}
+			slices.Sort(got)
+
+			want := []string{"p.go:4:2: call of g", "p.go:4:4: call of h", "panicking-node-rule crashed"}
+			if !slices.Equal(got, want) {
+				t.Errorf("got failures %q, want %q", got, want)
+			}
+			if calls.nodes != 2 {
+				t.Errorf("got %d nodes checked by the calls rule, want 2", calls.nodes)
+			}
+			if panicking.nodes != 2 {
+				t.Errorf("got %d nodes checked by the panicking rule, want 2", panicking.nodes)
+			}
+
+			var profiled []string
+			for _, rule := range pkg.profile.Report().Rules {
+				profiled = append(profiled, rule.Name)
+			}
+			slices.Sort(profiled)
+			if want := []string{"calls", "panicking-node-rule"}; !slices.Equal(profiled, want) {
+				t.Errorf("got profiled rules %q, want %q", profiled, want)
+			}
+		})
+	}
+}
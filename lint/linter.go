@@ -3,7 +3,9 @@ package lint
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
+	"go/build"
 	"go/token"
 	"log/slog"
 	"os"
@@ -11,6 +13,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	goversion "github.com/hashicorp/go-version"
 	"golang.org/x/mod/modfile"
@@ -25,6 +28,8 @@ type Linter struct {
 	reader         ReadFile
 	fileReadTokens chan struct{}
 	logger         *slog.Logger
+	profile        *Profile
+	coverage       *Coverage
 }
 
 // New creates a new Linter.
@@ -47,6 +52,16 @@ func (l *Linter) SetLogger(logger *slog.Logger) {
 	}
 }
 
+// SetProfile sets the profile collecting the time spent linting, or disables profiling if it is nil.
+func (l *Linter) SetProfile(profile *Profile) {
+	l.profile = profile
+}
+
+// SetCoverage sets the coverage collecting the files linted and skipped, or disables it if it is nil.
+func (l *Linter) SetCoverage(coverage *Coverage) {
+	l.coverage = coverage
+}
+
 func (l *Linter) readFile(path string) (result []byte, err error) {
 	if l.fileReadTokens != nil {
 		// "take" a token by writing to the channel.
@@ -69,6 +84,15 @@ var (
 
 // Lint lints a set of files with the specified rule.
 func (l *Linter) Lint(packages [][]string, ruleSet []Rule, config Config) (<-chan Failure, error) {
+	return l.LintContext(context.Background(), packages, ruleSet, config)
+}
+
+// LintContext lints a set of files with the specified rule.
+//
+// Once ctx is cancelled, no new package, file or rule is linted, and the returned channel
+// is closed after an internal failure reporting the cancellation, as soon as the work
+// in progress completes. Callers must keep receiving from the channel until it is closed.
+func (l *Linter) LintContext(ctx context.Context, packages [][]string, ruleSet []Rule, config Config) (<-chan Failure, error) {
 	failures := make(chan Failure)
 
 	perModVersions := map[string]*goversion.Version{}
@@ -109,12 +133,14 @@ func (l *Linter) Lint(packages [][]string, ruleSet []Rule, config Config) (<-cha
 		perPkgVersions[n] = v
 	}
 
+	contexts := buildContexts(config)
+
 	var wg errgroup.Group
 	for n := range packages {
 		wg.Go(func() error {
 			pkg := packages[n]
 			gover := perPkgVersions[n]
-			if err := l.lintPackage(pkg, gover, ruleSet, config, failures); err != nil {
+			if err := l.lintPackage(ctx, pkg, gover, contexts, ruleSet, config, failures); err != nil {
 				return fmt.Errorf("error during linting: %w", err)
 			}
 			return nil
@@ -129,10 +155,62 @@ func (l *Linter) Lint(packages [][]string, ruleSet []Rule, config Config) (<-cha
 		close(failures)
 	}()
 
+	if len(contexts) > 1 {
+		return DedupeFailures(failures), nil
+	}
 	return failures, nil
 }
 
-func (l *Linter) lintPackage(filenames []string, gover *goversion.Version, ruleSet []Rule, config Config, failures chan Failure) error {
+// lintPackage lints the files of a package, once per build context if any,
+// with only the files satisfying the build constraints of the context.
+func (l *Linter) lintPackage(ctx context.Context, filenames []string, gover *goversion.Version, contexts []*build.Context, ruleSet []Rule, config Config, failures chan Failure) error {
+	var names []string
+	contents := map[string][]byte{}
+	for _, filename := range filenames {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		content, err := l.readFile(filename)
+		if err != nil {
+			return err
+		}
+		if !config.IgnoreGeneratedHeader && isGenerated(content) {
+			l.coverage.add(filename, fileGenerated)
+			continue
+		}
+		names = append(names, filename)
+		contents[filename] = content
+	}
+
+	if contexts == nil {
+		return l.lintFiles(ctx, names, contents, gover, ruleSet, config, failures)
+	}
+
+	for _, buildCtx := range contexts {
+		var selected []string
+		for _, filename := range names {
+			match, err := matchBuildContext(buildCtx, filename, contents[filename])
+			if err != nil {
+				return err
+			}
+			if match {
+				selected = append(selected, filename)
+			}
+		}
+		l.logger.Debug("Files selected by build constraints", "goos", buildCtx.GOOS, "goarch", buildCtx.GOARCH, "files", selected)
+
+		if err := l.lintFiles(ctx, selected, contents, gover, ruleSet, config, failures); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lintFiles lints the given files, whose contents are already read, as three packages type checked separately,
+// as with go test: the library files, the in-package test files along with the library files,
+// and the files of the external test package, importing the package with its in-package test files.
+func (l *Linter) lintFiles(ctx context.Context, filenames []string, contents map[string][]byte, gover *goversion.Version, ruleSet []Rule, config Config, failures chan Failure) error {
 	if len(filenames) == 0 {
 		return nil
 	}
@@ -141,30 +219,59 @@ func (l *Linter) lintPackage(filenames []string, gover *goversion.Version, ruleS
 		fset:      token.NewFileSet(),
 		files:     map[string]*File{},
 		goVersion: gover,
+		dir:       filepath.Dir(filenames[0]),
+		profile:   l.profile,
+	}
+	test := &Package{
+		fset:      pkg.fset,
+		files:     map[string]*File{},
+		goVersion: gover,
+		dir:       pkg.dir,
+		profile:   l.profile,
+		library:   pkg,
+	}
+	xtest := &Package{
+		fset:      pkg.fset,
+		files:     map[string]*File{},
+		goVersion: gover,
+		dir:       pkg.dir,
+		profile:   l.profile,
+		underTest: test,
 	}
 	for _, filename := range filenames {
-		content, err := l.readFile(filename)
-		if err != nil {
-			return err
-		}
-		if !config.IgnoreGeneratedHeader && isGenerated(content) {
-			continue
-		}
-
+		content := contents[filename]
+		start := time.Now()
 		file, err := NewFile(filename, content, pkg)
+		pkg.profile.addParse(pkg.dir, time.Since(start))
 		if err != nil {
+			l.coverage.add(filename, fileInvalid)
 			addInvalidFileFailure(filename, err.Error(), failures)
 			continue
 		}
+		l.coverage.add(filename, fileLinted)
 		file.logger = l.logger
-		pkg.files[filename] = file
+		switch file.Variant() {
+		case TestVariant:
+			file.Pkg = test
+			test.files[filename] = file
+		case ExternalTestVariant:
+			file.Pkg = xtest
+			xtest.files[filename] = file
+		default:
+			pkg.files[filename] = file
+		}
 	}
 
-	if len(pkg.files) == 0 {
-		return nil
+	var eg errgroup.Group
+	for _, p := range []*Package{pkg, test, xtest} {
+		if len(p.files) == 0 {
+			continue
+		}
+		eg.Go(func() error {
+			return p.lint(ctx, ruleSet, config, failures)
+		})
 	}
-
-	return pkg.lint(ruleSet, config, failures)
+	return eg.Wait()
 }
 
 func detectGoMod(dir string) (rootDir string, ver *goversion.Version, err error) {
@@ -1,14 +1,21 @@
 package lint
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"go/ast"
 	"go/importer"
 	"go/token"
 	"go/types"
+	"os"
+	pathpkg "path"
+	"path/filepath"
 	"sync"
+	"time"
 
 	goversion "github.com/hashicorp/go-version"
+	"golang.org/x/mod/modfile"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/mgechev/revive/internal/astutils"
@@ -28,6 +35,43 @@ type Package struct {
 	sortable map[string]bool
 	// main is whether this is a "main" package.
 	main int
+
+	// dir is the directory of the package, and profile collects the time spent type checking it.
+	dir     string
+	profile *Profile
+
+	// library is, for the package of the in-package test files, the package of the other files,
+	// type checked along with the test files as with go test.
+	library *Package
+	// underTest is, for an external test package, the package it tests, including its in-package test files.
+	underTest *Package
+}
+
+// PackageVariant is the variant of a package a file is compiled in.
+type PackageVariant int
+
+const (
+	// LibraryVariant is the package itself, made of its non-test files.
+	LibraryVariant PackageVariant = iota
+	// TestVariant is the package compiled for its tests, with the _test.go files declaring the same package.
+	TestVariant
+	// ExternalTestVariant is the package of the _test.go files declaring a package with a _test suffix,
+	// which imports the package under test.
+	ExternalTestVariant
+)
+
+// String returns the name of the variant.
+func (v PackageVariant) String() string {
+	switch v {
+	case LibraryVariant:
+		return "library"
+	case TestVariant:
+		return "test"
+	case ExternalTestVariant:
+		return "external test"
+	default:
+		return fmt.Sprintf("PackageVariant(%d)", int(v))
+	}
 }
 
 var (
@@ -36,10 +80,14 @@ var (
 
 	// Go111 is a constant representing the Go version 1.11.
 	Go111 = goversion.Must(goversion.NewVersion("1.11"))
+	// Go113 is a constant representing the Go version 1.13.
+	Go113 = goversion.Must(goversion.NewVersion("1.13"))
 	// Go115 is a constant representing the Go version 1.15.
 	Go115 = goversion.Must(goversion.NewVersion("1.15"))
 	// Go118 is a constant representing the Go version 1.18.
 	Go118 = goversion.Must(goversion.NewVersion("1.18"))
+	// Go120 is a constant representing the Go version 1.20.
+	Go120 = goversion.Must(goversion.NewVersion("1.20"))
 	// Go121 is a constant representing the Go version 1.21.
 	Go121 = goversion.Must(goversion.NewVersion("1.21"))
 	// Go122 is a constant representing the Go version 1.22.
@@ -118,7 +166,7 @@ func (p *Package) TypeCheck() error {
 	config := &types.Config{
 		// By setting a no-op error reporter, the type checker does as much work as possible.
 		Error:    func(error) {},
-		Importer: importer.Default(),
+		Importer: p.importer(),
 	}
 	info := &types.Info{
 		Types:  map[ast.Expr]types.TypeAndValue{},
@@ -132,13 +180,21 @@ func (p *Package) TypeCheck() error {
 		anyFile = f
 		astFiles = append(astFiles, f.AST)
 	}
+	if p.library != nil {
+		for _, f := range p.library.Files() {
+			anyFile = f
+			astFiles = append(astFiles, f.AST)
+		}
+	}
 
 	if anyFile == nil {
 		// this is unlikely to happen, but technically guarantees anyFile to not be nil
 		return errors.New("no ast.File found")
 	}
 
+	start := time.Now()
 	typesPkg, err := check(config, anyFile.AST.Name.Name, p.fset, astFiles, info)
+	p.profile.addTypeCheck(p.dir, time.Since(start))
 
 	// Remember the typechecking info, even if config.Check failed,
 	// since we will get partial information.
@@ -148,6 +204,71 @@ func (p *Package) TypeCheck() error {
 	return err
 }
 
+// importer returns the importer of the package dependencies. An external test package
+// imports the package under test as type checked by the linter, including its in-package test files as with go test,
+// since it might not be installed and its export data would not have the declarations of the test files.
+func (p *Package) importer() types.Importer {
+	if p.underTest == nil {
+		return importer.Default()
+	}
+
+	// Type information is partial, rather than missing, when the package under test does not type check.
+	_ = p.underTest.TypeCheck()
+	pkg := p.underTest.TypesPkg()
+	if pkg == nil {
+		return importer.Default()
+	}
+	return &underTestImporter{
+		importer: importer.Default(),
+		path:     importPath(p.dir),
+		pkg:      pkg,
+	}
+}
+
+// underTestImporter resolves the import of the package under test to its types, and the other imports with importer.
+type underTestImporter struct {
+	importer types.Importer
+	// path is the import path of the package under test, or empty if it is unknown.
+	path string
+	pkg  *types.Package
+}
+
+func (i *underTestImporter) Import(path string) (*types.Package, error) {
+	// Without go.mod, the package under test is told apart by the last element of its path, as is customary.
+	isUnderTest := path == i.path || i.path == "" && pathpkg.Base(path) == i.pkg.Name()
+	if isUnderTest {
+		return i.pkg, nil
+	}
+	return i.importer.Import(path)
+}
+
+// importPath returns the import path of the package in dir, from the path of its module,
+// or an empty string if it is not in a module.
+func importPath(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	modFileName, err := retrieveModFile(dir)
+	if err != nil {
+		return ""
+	}
+	mod, err := os.ReadFile(modFileName) //nolint:gosec // ignore G304: potential file inclusion via variable
+	if err != nil {
+		return ""
+	}
+	modulePath := modfile.ModulePath(mod)
+	if modulePath == "" {
+		return ""
+	}
+
+	rel, err := filepath.Rel(filepath.Dir(modFileName), dir)
+	if err != nil {
+		return ""
+	}
+	return pathpkg.Join(modulePath, filepath.ToSlash(rel))
+}
+
 // check function encapsulates the call to [go/types.Config.Check] method and
 // recovers if the called method panics (see issue #59).
 func check(config *types.Config, n string, fset *token.FileSet, astFiles []*ast.File, info *types.Info) (p *types.Package, err error) {
@@ -209,12 +330,23 @@ func (p *Package) scanSortable() {
 	}
 }
 
-func (p *Package) lint(rules []Rule, config Config, failures chan Failure) error {
+func (p *Package) lint(ctx context.Context, rules []Rule, config Config, failures chan Failure) error {
+	defer func() {
+		for _, r := range rules {
+			if r, ok := r.(PackageStateRule); ok {
+				r.ReleasePackage(p)
+			}
+		}
+	}()
+
 	p.scanSortable()
 	var eg errgroup.Group
 	for _, file := range p.Files() {
 		eg.Go(func() error {
-			return file.lint(rules, config, failures)
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return file.lint(ctx, rules, config, failures)
 		})
 	}
 
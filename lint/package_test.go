@@ -0,0 +1,136 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// variantRule reports each linted file with its variant, the number of files of its package, whether its package type checks,
+// and whether the helper function declared by the in-package test files is in its scope.
+type variantRule struct{}
+
+func (*variantRule) Name() string { return "variant" }
+
+func (*variantRule) Apply(file *File, _ Arguments) []Failure {
+	failure := Failure{Confidence: 1, Failure: fmt.Sprintf("%s %s, %d files", file.Variant(), file.AST.Name.Name, len(file.Pkg.Files()))}
+	if err := file.Pkg.TypeCheck(); err != nil {
+		failure.Failure += ", type error"
+	}
+	if file.Pkg.TypesPkg().Scope().Lookup("helper") != nil {
+		failure.Failure += ", helper"
+	}
+	failure.Position.Start.Filename = file.Name
+	return []Failure{failure}
+}
+
+func TestLinter_externalTestPackage(t *testing.T) {
+	dir := t.TempDir()
+	sources := map[string]string{
+		"go.mod":         "module example.com/lib\n",
+		"lib.go":         "package lib\n\nfunc Exported() int { return 1 }\n",
+		"lib_test.go":    "package lib\n\nfunc helper() int { return Exported() }\n",
+		"export_test.go": "package lib\n\nvar Helper = helper\n",
+		"xtest_test.go":  "package lib_test\n\nimport \"example.com/lib\"\n\nvar _ int = lib.Exported() + lib.Helper()\n",
+	}
+	var filenames []string
+	for name, src := range sources {
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Ext(name) == ".go" {
+			filenames = append(filenames, filename)
+		}
+	}
+
+	l := New(os.ReadFile, 0)
+	failures, err := l.Lint([][]string{filenames}, []Rule{&variantRule{}}, Config{GoVersion: defaultGoVersion})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for failure := range failures {
+		got = append(got, filepath.Base(failure.Filename())+": "+failure.Failure)
+	}
+	slices.Sort(got)
+
+	// the library is type checked without the declarations of its tests
+	want := []string{
+		"export_test.go: test lib, 2 files, helper",
+		"lib.go: library lib, 1 files",
+		"lib_test.go: test lib, 2 files, helper",
+		"xtest_test.go: external test lib_test, 1 files",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got failures %q, want %q", got, want)
+	}
+}
+
+// packageStateRule records the packages it linted files of, and the packages released.
+type packageStateRule struct {
+	mu       sync.Mutex
+	linted   map[*Package]bool
+	released []*Package
+}
+
+func (*packageStateRule) Name() string { return "package-state" }
+
+func (r *packageStateRule) Apply(file *File, _ Arguments) []Failure {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.linted[file.Pkg] = true
+	return nil
+}
+
+func (r *packageStateRule) ReleasePackage(pkg *Package) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.released = append(r.released, pkg)
+}
+
+func TestLinter_releasePackage(t *testing.T) {
+	sources := map[string]string{
+		"pkg/a.go":      "package pkg\n",
+		"pkg/b.go":      "package pkg\n",
+		"pkg/a_test.go": "package pkg_test\n",
+	}
+	reader := func(path string) ([]byte, error) {
+		src, ok := sources[path]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return []byte(src), nil
+	}
+
+	r := &packageStateRule{linted: map[*Package]bool{}}
+	ruleConfig := RuleConfig{Exclude: []string{"pkg/b.go"}}
+	if err := ruleConfig.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	config := Config{GoVersion: defaultGoVersion, Rules: RulesConfig{"package-state": ruleConfig}}
+
+	l := New(reader, 0)
+	failures, err := l.Lint([][]string{{"pkg/a.go", "pkg/b.go", "pkg/a_test.go"}}, []Rule{r}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range failures {
+	}
+
+	// the packages are released once, even if the rule did not lint all their files
+	if len(r.released) != 2 || len(r.linted) != 2 {
+		t.Fatalf("expected the library and external test packages to be linted and released, got %d linted and %d released", len(r.linted), len(r.released))
+	}
+	for _, pkg := range r.released {
+		if !r.linted[pkg] {
+			t.Error("released a package that was not linted")
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package lint
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Profile collects the time spent linting, per rule and per package.
+//
+// A nil *Profile is valid and collects nothing.
+type Profile struct {
+	mu       sync.Mutex
+	rules    map[string]*RuleProfile
+	packages map[string]*PackageProfile
+}
+
+// RuleProfile is the time spent by a rule across all the linted files.
+type RuleProfile struct {
+	Name        string `json:"name"`
+	Invocations int    `json:"invocations"`
+	// Duration is the total wall time of the rule. It includes the time spent type checking
+	// a package, or waiting for it to be type checked, when the rule needs type information.
+	Duration time.Duration `json:"durationNs"`
+}
+
+// PackageProfile is the time spent preparing a package for the rules.
+type PackageProfile struct {
+	// Dir is the directory of the package.
+	Dir       string        `json:"dir"`
+	Files     int           `json:"files"`
+	Parse     time.Duration `json:"parseNs"`
+	TypeCheck time.Duration `json:"typeCheckNs"`
+}
+
+// ProfileReport is a snapshot of a [Profile].
+type ProfileReport struct {
+	// Rules are sorted by decreasing duration.
+	Rules []RuleProfile `json:"rules"`
+	// Packages are sorted by decreasing parse and type check duration.
+	Packages []PackageProfile `json:"packages"`
+}
+
+// NewProfile creates an empty profile.
+func NewProfile() *Profile {
+	return &Profile{
+		rules:    map[string]*RuleProfile{},
+		packages: map[string]*PackageProfile{},
+	}
+}
+
+func (p *Profile) addRule(name string, d time.Duration) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rp, ok := p.rules[name]
+	if !ok {
+		rp = &RuleProfile{Name: name}
+		p.rules[name] = rp
+	}
+	rp.Invocations++
+	rp.Duration += d
+}
+
+// addPackage updates, while holding the lock, the profile of the package in the given directory.
+func (p *Profile) addPackage(dir string, update func(*PackageProfile)) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pp, ok := p.packages[dir]
+	if !ok {
+		pp = &PackageProfile{Dir: dir}
+		p.packages[dir] = pp
+	}
+	update(pp)
+}
+
+func (p *Profile) addParse(dir string, d time.Duration) {
+	p.addPackage(dir, func(pp *PackageProfile) {
+		pp.Files++
+		pp.Parse += d
+	})
+}
+
+func (p *Profile) addTypeCheck(dir string, d time.Duration) {
+	p.addPackage(dir, func(pp *PackageProfile) {
+		pp.TypeCheck += d
+	})
+}
+
+// Report returns a snapshot of the profile.
+func (p *Profile) Report() ProfileReport {
+	if p == nil {
+		return ProfileReport{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	report := ProfileReport{
+		Rules:    make([]RuleProfile, 0, len(p.rules)),
+		Packages: make([]PackageProfile, 0, len(p.packages)),
+	}
+	for _, rp := range p.rules {
+		report.Rules = append(report.Rules, *rp)
+	}
+	for _, pp := range p.packages {
+		report.Packages = append(report.Packages, *pp)
+	}
+
+	slices.SortFunc(report.Rules, func(a, b RuleProfile) int {
+		return cmp.Or(-cmp.Compare(a.Duration, b.Duration), cmp.Compare(a.Name, b.Name))
+	})
+	slices.SortFunc(report.Packages, func(a, b PackageProfile) int {
+		return cmp.Or(-cmp.Compare(a.Parse+a.TypeCheck, b.Parse+b.TypeCheck), cmp.Compare(a.Dir, b.Dir))
+	})
+
+	return report
+}
+
+// WriteTable writes the report as two aligned tables, one for the rules and one for the packages.
+func (r ProfileReport) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "RULE\tINVOCATIONS\tTOTAL\tAVERAGE")
+	for _, rp := range r.Rules {
+		fmt.Fprintf(tw, "%s\t%d\t%v\t%v\n", rp.Name, rp.Invocations, rp.Duration, rp.Duration/time.Duration(max(rp.Invocations, 1)))
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "PACKAGE\tFILES\tPARSE\tTYPE CHECK")
+	for _, pp := range r.Packages {
+		fmt.Fprintf(tw, "%s\t%d\t%v\t%v\n", pp.Dir, pp.Files, pp.Parse, pp.TypeCheck)
+	}
+
+	return tw.Flush()
+}
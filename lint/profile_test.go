@@ -0,0 +1,107 @@
+package lint
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProfile_Report(t *testing.T) {
+	p := NewProfile()
+	p.addRule("fast-rule", time.Millisecond)
+	p.addRule("slow-rule", 3*time.Millisecond)
+	p.addRule("fast-rule", time.Millisecond)
+	p.addParse("a", time.Millisecond)
+	p.addParse("a", time.Millisecond)
+	p.addTypeCheck("a", time.Millisecond)
+	p.addParse("b", 5*time.Millisecond)
+
+	report := p.Report()
+
+	wantRules := []RuleProfile{
+		{Name: "slow-rule", Invocations: 1, Duration: 3 * time.Millisecond},
+		{Name: "fast-rule", Invocations: 2, Duration: 2 * time.Millisecond},
+	}
+	if len(report.Rules) != len(wantRules) {
+		t.Fatalf("got %d rules, want %d: %+v", len(report.Rules), len(wantRules), report.Rules)
+	}
+	for i, want := range wantRules {
+		if report.Rules[i] != want {
+			t.Errorf("rule %d: got %+v, want %+v", i, report.Rules[i], want)
+		}
+	}
+
+	wantPackages := []PackageProfile{
+		{Dir: "b", Files: 1, Parse: 5 * time.Millisecond},
+		{Dir: "a", Files: 2, Parse: 2 * time.Millisecond, TypeCheck: time.Millisecond},
+	}
+	if len(report.Packages) != len(wantPackages) {
+		t.Fatalf("got %d packages, want %d: %+v", len(report.Packages), len(wantPackages), report.Packages)
+	}
+	for i, want := range wantPackages {
+		if report.Packages[i] != want {
+			t.Errorf("package %d: got %+v, want %+v", i, report.Packages[i], want)
+		}
+	}
+
+	var sb strings.Builder
+	if err := report.WriteTable(&sb); err != nil {
+		t.Fatal(err)
+	}
+	want := `RULE       INVOCATIONS  TOTAL  AVERAGE
+slow-rule  1            3ms    3ms
+fast-rule  2            2ms    1ms
+
+PACKAGE  FILES  PARSE  TYPE CHECK
+b        1      5ms    0s
+a        2      2ms    1ms
+`
+	if sb.String() != want {
+		t.Errorf("got table:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
+
+func TestProfile_nil(t *testing.T) {
+	var p *Profile
+	p.addRule("rule", time.Millisecond)
+	p.addParse("a", time.Millisecond)
+
+	if report := p.Report(); len(report.Rules) != 0 || len(report.Packages) != 0 {
+		t.Errorf("expected an empty report, got %+v", report)
+	}
+}
+
+func TestFile_lint_profile(t *testing.T) {
+	profile := NewProfile()
+	rules := []Rule{
+		&fakeRule{name: "rule-a"},
+		&fakeRule{name: "rule-b"},
+	}
+	f := &File{
+		Name:   "test.go",
+		Pkg:    &Package{fset: token.NewFileSet(), profile: profile},
+		AST:    &ast.File{},
+		logger: slog.New(slog.DiscardHandler),
+	}
+
+	failures := make(chan Failure, 4)
+	for range 2 {
+		if err := f.lint(context.Background(), rules, Config{}, failures); err != nil {
+			t.Fatal("unexpected error from linting:", err)
+		}
+	}
+
+	report := profile.Report()
+	if len(report.Rules) != 2 {
+		t.Fatalf("expected 2 profiled rules, got %+v", report.Rules)
+	}
+	for _, rp := range report.Rules {
+		if rp.Invocations != 2 {
+			t.Errorf("expected rule %s to be invoked 2 times, got %d", rp.Name, rp.Invocations)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package lint
 
 import (
+	"go/ast"
 	"go/token"
 )
 
@@ -17,6 +18,44 @@ type Rule interface {
 	Apply(*File, Arguments) []Failure
 }
 
+// NodeRule defines a rule checking only some types of nodes. Rather than walking the file for each of them,
+// the linter traverses the file once for all the node rules, and dispatches each node to the rules checking its type.
+//
+// Apply can be implemented with [ApplyNodeRule], which is used when the rule is not applied
+// along with the other node rules, i.e. when rules have a time budget.
+type NodeRule interface {
+	Rule
+	// NodeTypes returns the types of the nodes checked by the rule, as typed nil pointers
+	// such as (*ast.CallExpr)(nil), or nil to check all the nodes.
+	NodeTypes() []ast.Node
+	// CheckNodes returns the function checking the nodes of the file, in depth-first order,
+	// and reporting the failures found with onFailure, or nil if the rule does not apply to the file.
+	CheckNodes(file *File, arguments Arguments, onFailure func(Failure)) func(ast.Node)
+}
+
+// ApplyNodeRule applies the node rule to the file on its own.
+func ApplyNodeRule(rule NodeRule, file *File, arguments Arguments) []Failure {
+	var failures []Failure
+	check := rule.CheckNodes(file, arguments, func(failure Failure) {
+		failures = append(failures, failure)
+	})
+	if check == nil {
+		return nil
+	}
+
+	file.Inspector().Preorder(rule.NodeTypes(), check)
+	return failures
+}
+
+// PackageStateRule defines a rule holding state for each package while its files are linted,
+// such as a result shared by the files of the package.
+type PackageStateRule interface {
+	Rule
+	// ReleasePackage is called once the linting of the package ended, whether all its files
+	// were linted by the rule or not, e.g. when some were excluded or the linting was canceled.
+	ReleasePackage(*Package)
+}
+
 // ConfigurableRule defines an abstract configurable rule interface.
 type ConfigurableRule interface {
 	Configure(Arguments) error
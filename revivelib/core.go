@@ -2,13 +2,16 @@
 package revivelib
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"maps"
 	"os"
 	"slices"
 	"strings"
+	"sync"
 
 	"github.com/mgechev/dots"
 
@@ -20,31 +23,86 @@ import (
 // Revive is responsible for running linters and formatters
 // and returning a set of results.
 type Revive struct {
-	config       *lint.Config
-	lintingRules []lint.Rule
-	logger       *slog.Logger
-	maxOpenFiles int
+	config          *lint.Config
+	lintingRules    []lint.Rule
+	extraRules      []ExtraRule
+	logger          *slog.Logger
+	maxOpenFiles    int
+	profile         *lint.Profile
+	readFile        lint.ReadFile
+	resolvePackages PackageResolver
+	sortOutput      bool
+	stableOutput    bool
 }
 
-// New creates a new instance of [Revive] lint runner.
+// New creates a new instance of [Revive] lint runner, with the given extra rules.
+//
+// The configuration is not modified, so it can be shared by several instances.
+// It can be nil to use the default configuration.
 func New(
 	conf *lint.Config,
 	setExitStatus bool,
 	maxOpenFiles int,
 	extraRules ...ExtraRule,
 ) (*Revive, error) {
-	logger, err := logging.GetLogger()
-	if err != nil {
-		return nil, fmt.Errorf("initializing revive - getting logger: %w", err)
+	options := make([]Option, len(extraRules))
+	for i, extraRule := range extraRules {
+		options[i] = extraRule
+	}
+	return NewWithOptions(conf, setExitStatus, maxOpenFiles, options...)
+}
+
+// NewWithOptions is like [New], with options, including the extra rules.
+//
+// The configuration can be nil if it is set with the [WithConfigReader] option, or to use the default configuration.
+// Without the [WithLogger] option, the logger is configured by the REVIVE_LOG_LEVEL environment variable.
+// Each instance configures its own copies of the rules, so instances with different configurations
+// can lint concurrently.
+func NewWithOptions(
+	conf *lint.Config,
+	setExitStatus bool,
+	maxOpenFiles int,
+	options ...Option,
+) (*Revive, error) {
+	r := &Revive{
+		maxOpenFiles:    maxOpenFiles,
+		readFile:        os.ReadFile,
+		resolvePackages: getPackages,
+		sortOutput:      true,
+	}
+	if conf != nil {
+		r.config = cloneConfig(conf)
+	}
+	for _, option := range options {
+		if err := option.apply(r); err != nil {
+			return nil, fmt.Errorf("initializing revive - applying options: %w", err)
+		}
 	}
 
+	if r.logger == nil {
+		logger, err := logging.GetLogger()
+		if err != nil {
+			return nil, fmt.Errorf("initializing revive - getting logger: %w", err)
+		}
+		r.logger = logger
+	}
+
+	if r.config == nil {
+		defaultConf, err := config.GetConfig("")
+		if err != nil {
+			return nil, fmt.Errorf("initializing revive - getting default config: %w", err)
+		}
+		r.config = defaultConf
+	}
+	conf = r.config
+
 	if setExitStatus {
 		conf.ErrorCode = 1
 		conf.WarningCode = 1
 	}
 
-	extraRuleInstances := make([]lint.Rule, len(extraRules))
-	for i, extraRule := range extraRules {
+	extraRuleInstances := make([]lint.Rule, len(r.extraRules))
+	for i, extraRule := range r.extraRules {
 		extraRuleInstances[i] = extraRule.Rule
 
 		ruleName := extraRule.Rule.Name()
@@ -59,19 +117,49 @@ func New(
 	if err != nil {
 		return nil, fmt.Errorf("initializing revive - getting lint rules: %w", err)
 	}
+	r.lintingRules = lintingRules
 
-	logger.Info("Config loaded", "rules", slices.Collect(maps.Keys(conf.Rules)))
+	r.logger.Info("Config loaded", "rules", slices.Collect(maps.Keys(conf.Rules)))
 
-	return &Revive{
-		logger:       logger,
-		config:       conf,
-		lintingRules: lintingRules,
-		maxOpenFiles: maxOpenFiles,
-	}, nil
+	return r, nil
+}
+
+// cloneConfig returns a copy of the configuration, whose rules can be modified.
+func cloneConfig(conf *lint.Config) *lint.Config {
+	clone := *conf
+	clone.Rules = maps.Clone(conf.Rules)
+	if clone.Rules == nil {
+		clone.Rules = lint.RulesConfig{}
+	}
+	return &clone
+}
+
+// SetProfile sets the profile collecting the time spent by each rule, parsing and type checking
+// during the subsequent lints, or disables profiling if it is nil.
+// The profile is complete once the failures channel is closed.
+func (r *Revive) SetProfile(profile *lint.Profile) {
+	r.profile = profile
 }
 
 // Lint the included patterns, skipping excluded ones.
 func (r *Revive) Lint(patterns ...*LintPattern) (<-chan lint.Failure, error) {
+	return r.LintWithOverlay(context.Background(), nil, patterns...)
+}
+
+// LintWithOverlay is like [Revive.Lint], but the files in overlay, keyed by path, are used instead of
+// the linted files on disk. Overlay files that don't exist on disk are added to the package of their
+// directory, or grouped into new packages if no linted package is in their directory.
+func (r *Revive) LintWithOverlay(ctx context.Context, overlay map[string][]byte, patterns ...*LintPattern) (<-chan lint.Failure, error) {
+	packages, err := r.packages(overlay, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.lint(ctx, packages, overlay, nil, nil)
+}
+
+// packages returns the packages matching the patterns, with the overlay files.
+func (r *Revive) packages(overlay map[string][]byte, patterns []*LintPattern) ([][]string, error) {
 	includePatterns := []string{}
 	excludePatterns := []string{}
 
@@ -92,26 +180,45 @@ func (r *Revive) Lint(patterns ...*LintPattern) (<-chan lint.Failure, error) {
 		excludePatterns = []string{"vendor/..."}
 	}
 
-	packages, err := getPackages(includePatterns, excludePatterns)
+	packages, err := r.resolvePackages(includePatterns, excludePatterns)
 	if err != nil {
 		return nil, fmt.Errorf("linting - getting packages: %w", err)
 	}
 
-	revive := lint.New(func(file string) ([]byte, error) {
-		contents, err := os.ReadFile(file) //nolint:gosec // ignore G304: potential file inclusion via variable
-		if err != nil {
-			return nil, fmt.Errorf("reading file %v: %w", file, err)
-		}
+	return addOverlayFiles(packages, overlay), nil
+}
+
+// LintSources lints the given in-memory sources, keyed by file path, without reading anything from disk.
+// The sources are grouped into packages by directory and package clause.
+func (r *Revive) LintSources(ctx context.Context, sources map[string][]byte) (<-chan lint.Failure, error) {
+	return r.lint(ctx, groupSources(sources), sources, nil, nil)
+}
+
+func (r *Revive) lint(
+	ctx context.Context,
+	packages [][]string,
+	overlay map[string][]byte,
+	coverage *lint.Coverage,
+	onCollapse func(CollapsedFailure),
+) (<-chan lint.Failure, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("linting: %w", err)
+	}
 
-		return contents, nil
-	}, r.maxOpenFiles)
+	revive := lint.New(overlayReader(overlay, r.readFile), r.maxOpenFiles)
 	revive.SetLogger(r.logger)
+	revive.SetProfile(r.profile)
+	revive.SetCoverage(coverage)
 
-	failures, err := revive.Lint(packages, r.lintingRules, *r.config)
+	failures, err := revive.LintContext(ctx, packages, r.lintingRules, *r.config)
 	if err != nil {
 		return nil, fmt.Errorf("linting - retrieving failures channel: %w", err)
 	}
 
+	failures = lint.DedupeFailures(failures)
+	if len(r.config.OverlappingRules) > 0 {
+		failures = r.collapseOverlappingFailures(failures, onCollapse)
+	}
 	return failures, nil
 }
 
@@ -120,57 +227,147 @@ func (r *Revive) Format(
 	formatterName string,
 	failuresChan <-chan lint.Failure,
 ) (output string, exitCode int, err error) {
-	conf := r.config
-	formatChan := make(chan lint.Failure)
-	exitChan := make(chan bool)
+	return r.FormatWithArguments(formatterName, nil, failuresChan)
+}
 
-	formatter, err := config.GetFormatter(formatterName)
+// FormatWithArguments is like [Revive.Format] but configures the formatter with the given arguments.
+func (r *Revive) FormatWithArguments(
+	formatterName string,
+	arguments lint.FormatterArguments,
+	failuresChan <-chan lint.Failure,
+) (output string, exitCode int, err error) {
+	formatter, err := config.NewFormatter(formatterName, arguments)
 	if err != nil {
 		return "", 0, fmt.Errorf("formatting - getting formatter: %w", err)
 	}
 
-	var (
-		out       string
-		formatErr error
-	)
+	outputs, exitCode, err := r.format([]lint.Formatter{formatter}, nil, failuresChan)
+	if err != nil {
+		return "", exitCode, err
+	}
 
-	go func() {
-		out, formatErr = formatter.Format(formatChan, *conf)
+	return outputs[0], exitCode, nil
+}
 
-		exitChan <- true
-	}()
+// format fans the failures out to all the given formatters
+// and returns their outputs, in the same order, along with the exit code.
+//
+// When writers is not nil, formatters implementing [lint.StreamingFormatter]
+// write directly to the writer at the same index, and their output is left empty.
+//
+// Unless sorting is disabled, the other formatters receive the failures once all of them are reported,
+// sorted by file, line, column and rule. So do streaming formatters with a stable output.
+func (r *Revive) format(
+	formatters []lint.Formatter,
+	writers []io.Writer,
+	failuresChan <-chan lint.Failure,
+) (outputs []string, exitCode int, err error) {
+	conf := r.config
+	outputs = make([]string, len(formatters))
+	formatErrs := make([]error, len(formatters))
+	formatChans := make([]chan lint.Failure, len(formatters))
+
+	streamed := make([]bool, len(formatters))
+	sorted := make([]bool, len(formatters))
+	for i, formatter := range formatters {
+		_, isStreaming := formatter.(lint.StreamingFormatter)
+		streamed[i] = isStreaming && writers != nil
+		sorted[i] = r.stableOutput || r.sortOutput && !streamed[i]
+	}
 
+	var wg sync.WaitGroup
+	for i, formatter := range formatters {
+		formatChans[i] = make(chan lint.Failure)
+		wg.Go(func() {
+			if streamed[i] {
+				formatErrs[i] = formatter.(lint.StreamingFormatter).FormatTo(writers[i], formatChans[i], *conf)
+			} else {
+				outputs[i], formatErrs[i] = formatter.Format(formatChans[i], *conf)
+			}
+			// drain failures left unread by a formatter that stopped early
+			for range formatChans[i] {
+			}
+		})
+	}
+
+	var buffered []lint.Failure
 	for failure := range failuresChan {
 		if failure.Confidence < conf.Confidence {
 			continue
 		}
 
-		if exitCode == 0 {
-			exitCode = conf.WarningCode
+		exitCode = r.exitCode(exitCode, failure)
+
+		for i, formatChan := range formatChans {
+			if !sorted[i] {
+				formatChan <- failure
+			}
+		}
+		if slices.Contains(sorted, true) {
+			buffered = append(buffered, failure)
 		}
+	}
 
-		if c, ok := conf.Rules[failure.RuleName]; ok && c.Severity == lint.SeverityError {
-			exitCode = conf.ErrorCode
+	slices.SortStableFunc(buffered, compareFailures)
+	for _, failure := range buffered {
+		for i, formatChan := range formatChans {
+			if sorted[i] {
+				formatChan <- failure
+			}
 		}
+	}
 
-		if c, ok := conf.Directives[failure.RuleName]; ok && c.Severity == lint.SeverityError {
-			exitCode = conf.ErrorCode
+	for _, formatChan := range formatChans {
+		close(formatChan)
+	}
+	wg.Wait()
+
+	for i, formatErr := range formatErrs {
+		if formatErr != nil {
+			return nil, exitCode, fmt.Errorf("formatting with %s: %w", formatters[i].Name(), formatErr)
 		}
+	}
 
-		formatChan <- failure
+	computed := exitCode
+	for _, formatter := range formatters {
+		ef, ok := formatter.(lint.ExitCodeFormatter)
+		if !ok {
+			continue
+		}
+		if len(formatters) == 1 {
+			exitCode = ef.ExitCode(computed)
+			continue
+		}
+		// the exit code also stands for the other outputs: a formatter can only raise it
+		exitCode = max(exitCode, ef.ExitCode(computed))
 	}
 
-	close(formatChan)
-	<-exitChan
+	return outputs, exitCode, nil
+}
 
-	if formatErr != nil {
-		return "", exitCode, fmt.Errorf("formatting: %w", formatErr)
+// exitCode returns the exit code once the failure is reported, given the exit code before it.
+func (r *Revive) exitCode(exitCode int, failure lint.Failure) int {
+	if r.severity(failure) == lint.SeverityError {
+		return r.config.ErrorCode
 	}
+	if exitCode == 0 {
+		return r.config.WarningCode
+	}
+	return exitCode
+}
 
-	return out, exitCode, nil
+// severity returns the severity of the failure, set in the configuration of its rule or directive.
+func (r *Revive) severity(failure lint.Failure) lint.Severity {
+	if c, ok := r.config.Rules[failure.RuleName]; ok && c.Severity == lint.SeverityError {
+		return lint.SeverityError
+	}
+	if c, ok := r.config.Directives[failure.RuleName]; ok && c.Severity == lint.SeverityError {
+		return lint.SeverityError
+	}
+	return lint.SeverityWarning
 }
 
-func getPackages(includePatterns []string, excludePatterns ArrayFlags) ([][]string, error) {
+func getPackages(includePatterns, excludePatterns []string) ([][]string, error) {
 	globs := normalizeSplit(includePatterns)
 	if len(globs) == 0 {
 		globs = append(globs, ".")
@@ -1,6 +1,7 @@
 package revivelib
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/mgechev/revive/config"
@@ -66,3 +67,25 @@ func getMockRevive(t *testing.T) *Revive {
 
 	return revive
 }
+
+func TestGroupSources(t *testing.T) {
+	sources := map[string][]byte{
+		"pkg/b.go":      []byte("package pkg"),
+		"pkg/a.go":      []byte("package pkg"),
+		"pkg/a_test.go": []byte("package pkg_test"),
+		"other/main.go": []byte("package main"),
+		"other/bad.go":  []byte("not go"),
+	}
+
+	got := groupSources(sources)
+
+	want := [][]string{
+		{"other/bad.go"},
+		{"other/main.go"},
+		{"pkg/a.go", "pkg/b.go"},
+		{"pkg/a_test.go"},
+	}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("Expected packages %v, got %v.", want, got)
+	}
+}
@@ -1,7 +1,16 @@
 package revivelib_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"go/token"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/mgechev/revive/config"
@@ -35,6 +44,71 @@ func TestReviveLint(t *testing.T) {
 	}
 }
 
+func TestReviveLintSources(t *testing.T) {
+	// ARRANGE
+	revive := getMockRevive(t)
+	sources := map[string][]byte{
+		"virtual/a.go": []byte("// Package virtual is linted from memory.\npackage virtual\n\nfunc a() error {\n\tif err := b(); err != nil {\n\t\treturn err\n\t}\n\treturn nil\n}\n"),
+		"virtual/b.go": []byte("package virtual\n\nfunc b() error { return nil }\n"),
+	}
+
+	// ACT
+	failures, err := revive.LintSources(context.Background(), sources)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ASSERT
+	var got []string
+	for failure := range failures {
+		got = append(got, fmt.Sprintf("%s:%d %s", failure.Filename(), failure.Position.Start.Line, failure.RuleName))
+	}
+	want := []string{"virtual/a.go:5 if-return"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Expected failures %v, got %v.", want, got)
+	}
+}
+
+func TestReviveLintWithOverlay(t *testing.T) {
+	// ARRANGE
+	revive := getMockRevive(t)
+	overlay := map[string][]byte{
+		"../testdata/if_return.go":       []byte("// Package fixtures is linted from memory.\npackage fixtures\n\nfunc a() error { return nil }\n"),
+		"../testdata/virtual_overlay.go": []byte("package fixtures\n\nfunc b() error {\n\tif err := a(); err != nil {\n\t\treturn err\n\t}\n\treturn nil\n}\n"),
+	}
+
+	// ACT
+	failures, err := revive.LintWithOverlay(context.Background(), overlay, revivelib.Include("../testdata/if_return.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ASSERT
+	var got []string
+	for failure := range failures {
+		got = append(got, fmt.Sprintf("%s:%d %s", failure.Filename(), failure.Position.Start.Line, failure.RuleName))
+	}
+	want := []string{"../testdata/virtual_overlay.go:4 if-return"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Expected failures %v, got %v.", want, got)
+	}
+}
+
+func TestReviveLintSourcesCancelled(t *testing.T) {
+	// ARRANGE
+	revive := getMockRevive(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// ACT
+	_, err := revive.LintSources(ctx, map[string][]byte{"virtual/a.go": []byte("package virtual")})
+
+	// ASSERT
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected error %v, got %v.", context.Canceled, err)
+	}
+}
+
 func TestReviveFormat(t *testing.T) {
 	t.Setenv("NO_COLOR", "true")
 	// ARRANGE
@@ -71,6 +145,456 @@ func TestReviveFormat(t *testing.T) {
 	}
 }
 
+func TestReviveFormatToOutputs(t *testing.T) {
+	t.Setenv("NO_COLOR", "true")
+
+	// ARRANGE
+	revive := getMockRevive(t)
+	failuresChan, err := revive.Lint(revivelib.Include("../testdata/if_return.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unixOut, jsonOut strings.Builder
+
+	// ACT
+	exitCode, err := revive.FormatToOutputs([]revivelib.FormatterOutput{
+		{Name: "unix", Writer: &unixOut},
+		{Name: "json", Writer: &jsonOut},
+	}, failuresChan)
+
+	// ASSERT
+	if err != nil {
+		t.Fatal(err)
+	}
+	const wantUnixMsg = "[if-return] redundant if ...; err != nil check, just return error instead."
+	if got := strings.Count(unixOut.String(), wantUnixMsg); got != 3 {
+		t.Errorf("Expected unix output\n'%s'\nto contain 3 times '%s', got %d.", unixOut.String(), wantUnixMsg, got)
+	}
+	const wantJSONMsg = `"RuleName":"unreachable-code"`
+	if got := strings.Count(jsonOut.String(), wantJSONMsg); got != 2 {
+		t.Errorf("Expected json output\n'%s'\nto contain 2 times '%s', got %d.", jsonOut.String(), wantJSONMsg, got)
+	}
+	const expected = 1
+	if exitCode != expected {
+		t.Fatalf("Expected exit code to be %d, but it was %d.", expected, exitCode)
+	}
+}
+
+func TestReviveFormatToOutputsStatsCompare(t *testing.T) {
+	t.Setenv("NO_COLOR", "true")
+
+	// ARRANGE
+	revive := getMockRevive(t)
+	failuresChan, err := revive.Lint(revivelib.Include("../testdata/if_return.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var baseline strings.Builder
+	if _, err := revive.FormatToOutputs([]revivelib.FormatterOutput{{Name: "json", Writer: &baseline}}, failuresChan); err != nil {
+		t.Fatal(err)
+	}
+	comparePath := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(comparePath, []byte(baseline.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		outputs []string
+		want    int
+	}{
+		"stats alone":       {outputs: []string{"stats"}, want: 0},
+		"stats and another": {outputs: []string{"stats", "json"}, want: 1},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			failuresChan, err := revive.Lint(revivelib.Include("../testdata/if_return.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var outputs []revivelib.FormatterOutput
+			for _, name := range tc.outputs {
+				outputs = append(outputs, revivelib.FormatterOutput{
+					Name:      name,
+					Arguments: lint.FormatterArguments{"compare": comparePath},
+					Writer:    &strings.Builder{},
+				})
+			}
+
+			// ACT
+			exitCode, err := revive.FormatToOutputs(outputs, failuresChan)
+
+			// ASSERT
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exitCode != tc.want {
+				t.Errorf("got exit code %d, want %d", exitCode, tc.want)
+			}
+		})
+	}
+}
+
+func TestReviveFormatSorted(t *testing.T) {
+	t.Setenv("NO_COLOR", "true")
+
+	failureAt := func(filename string, line, column int, ruleName string) lint.Failure {
+		failure := lint.Failure{Confidence: 1, RuleName: ruleName}
+		failure.Position.Start = token.Position{Filename: filename, Line: line, Column: column}
+		failure.Failure = fmt.Sprintf("failure at %s by %s", failure.Position.Start, ruleName)
+		return failure
+	}
+	// in the order of arrival
+	failures := []lint.Failure{
+		failureAt("b.go", 1, 1, "rule"),
+		failureAt("a.go", 2, 1, "rule"),
+		failureAt("a.go", 1, 5, "rule"),
+		failureAt("a.go", 1, 5, "other-rule"),
+	}
+	arrival := []string{
+		"failure at b.go:1:1 by rule",
+		"failure at a.go:2:1 by rule",
+		"failure at a.go:1:5 by rule",
+		"failure at a.go:1:5 by other-rule",
+	}
+	sorted := []string{
+		"failure at a.go:1:5 by other-rule",
+		"failure at a.go:1:5 by rule",
+		"failure at a.go:2:1 by rule",
+		"failure at b.go:1:1 by rule",
+	}
+
+	tests := map[string]struct {
+		options            []revivelib.Option
+		wantUnix, wantJSON []string
+	}{
+		"default": {
+			wantUnix: arrival,
+			wantJSON: sorted,
+		},
+		"not sorted": {
+			options:  []revivelib.Option{revivelib.WithSortedOutput(false)},
+			wantUnix: arrival,
+			wantJSON: arrival,
+		},
+		"stable": {
+			options:  []revivelib.Option{revivelib.WithStableOutput(true)},
+			wantUnix: sorted,
+			wantJSON: sorted,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			revive, err := revivelib.NewWithOptions(nil, false, 0, tc.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			failuresChan := make(chan lint.Failure, len(failures))
+			for _, failure := range failures {
+				failuresChan <- failure
+			}
+			close(failuresChan)
+
+			var unixOut, jsonOut strings.Builder
+			_, err = revive.FormatToOutputs([]revivelib.FormatterOutput{
+				{Name: "unix", Writer: &unixOut},
+				{Name: "json", Writer: &jsonOut},
+			}, failuresChan)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// order returns the failure messages in the order they appear in the output.
+			order := func(output string) []string {
+				result := slices.Clone(arrival)
+				slices.SortFunc(result, func(a, b string) int {
+					return strings.Index(output, a) - strings.Index(output, b)
+				})
+				return result
+			}
+			if got := order(unixOut.String()); !slices.Equal(got, tc.wantUnix) {
+				t.Errorf("got unix output in order %q, want %q", got, tc.wantUnix)
+			}
+			if got := order(jsonOut.String()); !slices.Equal(got, tc.wantJSON) {
+				t.Errorf("got json output in order %q, want %q", got, tc.wantJSON)
+			}
+		})
+	}
+}
+
+func TestReviveLintResult(t *testing.T) {
+	// ARRANGE
+	const ifReturn = "func %s() error {\n\tif err := %[1]s(); err != nil {\n\t\treturn err\n\t}\n\treturn nil\n}\n"
+	overlay := map[string][]byte{
+		"virtual/b.go":   []byte("package virtual\n\n" + fmt.Sprintf(ifReturn, "b")),
+		"virtual/a.go":   []byte("package virtual\n\n" + fmt.Sprintf(ifReturn, "a") + "\n" + fmt.Sprintf(ifReturn, "c")),
+		"virtual/gen.go": []byte("// Code generated by hand. DO NOT EDIT.\n\npackage virtual\n"),
+		"virtual/bad.go": []byte("package virtual\n\nfunc"),
+	}
+	revive, err := revivelib.NewWithOptions(
+		nil,
+		true,
+		0,
+		revivelib.WithConfigReader(strings.NewReader("[rule.if-return]\nseverity = \"error\"\n")),
+		revivelib.WithPackageResolver(func(_, _ []string) ([][]string, error) { return nil, nil }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ACT
+	result, err := revive.LintResult(context.Background(), overlay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ASSERT
+	var got []string
+	for _, failure := range result.Failures {
+		got = append(got, fmt.Sprintf("%s:%d %s", failure.Filename(), failure.Position.Start.Line, failure.RuleName))
+	}
+	want := []string{"virtual/a.go:4 if-return", "virtual/a.go:11 if-return", "virtual/b.go:4 if-return", "virtual/bad.go:3 "}
+	if !slices.Equal(got, want) {
+		t.Errorf("got failures %q, want %q", got, want)
+	}
+	if result.Severities[lint.SeverityError] != 3 || result.Severities[lint.SeverityWarning] != 1 {
+		t.Errorf("got severities %v, want 3 errors and 1 warning", result.Severities)
+	}
+	if result.Rules["if-return"] != 3 {
+		t.Errorf("got rules %v, want 3 if-return failures", result.Rules)
+	}
+	if result.ExitCode != 1 {
+		t.Errorf("got exit code %d, want 1", result.ExitCode)
+	}
+	if want := []string{"virtual/a.go", "virtual/b.go"}; !slices.Equal(result.Files, want) {
+		t.Errorf("got files %q, want %q", result.Files, want)
+	}
+	if want := []string{"virtual/gen.go"}; !slices.Equal(result.GeneratedFiles, want) {
+		t.Errorf("got generated files %q, want %q", result.GeneratedFiles, want)
+	}
+	if want := []string{"virtual/bad.go"}; !slices.Equal(result.InvalidFiles, want) {
+		t.Errorf("got invalid files %q, want %q", result.InvalidFiles, want)
+	}
+	if len(result.Packages) != 1 || len(result.Packages[0]) != 4 {
+		t.Errorf("got packages %q, want a package of 4 files", result.Packages)
+	}
+	if len(result.Diagnostics) != 0 {
+		t.Errorf("got diagnostics %v, want none", result.Diagnostics)
+	}
+}
+
+func TestReviveLintDeduplicates(t *testing.T) {
+	// ARRANGE
+	const src = "package p\n\nimport \"fmt\"\n\nvar name = \"revive\"\n\nvar (\n\t_ = fmt.Sprintf(\"%s\", name)\n\t_ = fmt.Sprintf(\"%d\", 1)\n)\n"
+	const conf = `
+[pattern.sprintf-string]
+pattern = 'fmt.Sprintf("%s", $x)'
+message = "use $x"
+
+[pattern.any-sprintf]
+pattern = 'fmt.Sprintf($*_)'
+message = "avoid fmt.Sprintf"
+`
+	readFile := func(string) ([]byte, error) { return []byte(src), nil }
+	// overlapping patterns resolve to the same package twice
+	resolvePackages := func(_, _ []string) ([][]string, error) {
+		return [][]string{{"p/p.go"}, {"p/p.go"}}, nil
+	}
+
+	tests := map[string]struct {
+		overlapping   string
+		want          []string
+		wantCollapsed []string
+	}{
+		"identical failures": {
+			want: []string{"p/p.go:8 any-sprintf", "p/p.go:8 sprintf-string", "p/p.go:9 any-sprintf"},
+		},
+		"overlapping rules": {
+			overlapping:   "[overlapping-rules]\nsprintf-string = [\"any-sprintf\"]\n",
+			want:          []string{"p/p.go:8 sprintf-string", "p/p.go:9 any-sprintf"},
+			wantCollapsed: []string{"p/p.go:8 any-sprintf won by sprintf-string"},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			revive, err := revivelib.NewWithOptions(
+				nil,
+				false,
+				0,
+				revivelib.WithConfigReader(strings.NewReader(conf+tc.overlapping)),
+				revivelib.WithFileReader(readFile),
+				revivelib.WithPackageResolver(resolvePackages),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// ACT
+			result, err := revive.LintResult(context.Background(), nil, revivelib.Include("./..."), revivelib.Include("./p/..."))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// ASSERT
+			var got []string
+			for _, failure := range result.Failures {
+				got = append(got, fmt.Sprintf("%s:%d %s", failure.Filename(), failure.Position.Start.Line, failure.RuleName))
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got failures %q, want %q", got, tc.want)
+			}
+			var gotCollapsed []string
+			for _, c := range result.Collapsed {
+				gotCollapsed = append(gotCollapsed, fmt.Sprintf("%s:%d %s won by %s", c.Failure.Filename(), c.Failure.Position.Start.Line, c.Failure.RuleName, c.Winner.RuleName))
+			}
+			if !slices.Equal(gotCollapsed, tc.wantCollapsed) {
+				t.Errorf("got collapsed failures %q, want %q", gotCollapsed, tc.wantCollapsed)
+			}
+		})
+	}
+}
+
+func TestReviveOptions(t *testing.T) {
+	// ARRANGE
+	sources := map[string]string{
+		"mem/a.go": "package mem\n\nfunc a() error {\n\tif err := a(); err != nil {\n\t\treturn err\n\t}\n\treturn nil\n}\n",
+	}
+	readFile := func(path string) ([]byte, error) {
+		src, ok := sources[path]
+		if !ok {
+			return nil, fmt.Errorf("unknown file %s", path)
+		}
+		return []byte(src), nil
+	}
+	var resolved []string
+	resolvePackages := func(includePatterns, _ []string) ([][]string, error) {
+		resolved = includePatterns
+		return [][]string{{"mem/a.go"}}, nil
+	}
+	var logs strings.Builder
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	revive, err := revivelib.NewWithOptions(
+		nil,
+		false,
+		0,
+		revivelib.WithConfigReader(strings.NewReader("[rule.if-return]\n")),
+		revivelib.WithLogger(logger),
+		revivelib.WithFileReader(readFile),
+		revivelib.WithPackageResolver(resolvePackages),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ACT
+	failures, err := revive.Lint(revivelib.Include("mem/..."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ASSERT
+	var got []string
+	for failure := range failures {
+		got = append(got, fmt.Sprintf("%s:%d %s", failure.Filename(), failure.Position.Start.Line, failure.RuleName))
+	}
+	if want := []string{"mem/a.go:4 if-return"}; !slices.Equal(got, want) {
+		t.Errorf("got failures %q, want %q", got, want)
+	}
+	if want := []string{"mem/..."}; !slices.Equal(resolved, want) {
+		t.Errorf("got resolved patterns %q, want %q", resolved, want)
+	}
+	if !strings.Contains(logs.String(), `msg="Config loaded" rules=[if-return]`) {
+		t.Errorf("expected the logger to get the logs, got %q", logs.String())
+	}
+}
+
+func TestReviveSessionsConfigureOwnRules(t *testing.T) {
+	// ARRANGE
+	sources := map[string]string{
+		"mem/a.go": "package mem\n\nfunc a(x, y, z int) {}\n",
+	}
+	readFile := func(path string) ([]byte, error) {
+		src, ok := sources[path]
+		if !ok {
+			return nil, fmt.Errorf("unknown file %s", path)
+		}
+		return []byte(src), nil
+	}
+	resolvePackages := func(_, _ []string) ([][]string, error) {
+		return [][]string{{"mem/a.go"}}, nil
+	}
+	newSession := func(maxArguments int) *revivelib.Revive {
+		revive, err := revivelib.NewWithOptions(
+			nil,
+			false,
+			0,
+			revivelib.WithConfigReader(strings.NewReader(fmt.Sprintf("[rule.argument-limit]\narguments = [%d]\n", maxArguments))),
+			revivelib.WithLogger(slog.New(slog.DiscardHandler)),
+			revivelib.WithFileReader(readFile),
+			revivelib.WithPackageResolver(resolvePackages),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return revive
+	}
+
+	// ACT
+	strict, lenient := newSession(2), newSession(3)
+	var wg sync.WaitGroup
+	got := make([]int, 2)
+	for i, revive := range []*revivelib.Revive{strict, lenient} {
+		wg.Go(func() {
+			failures, err := revive.Lint(revivelib.Include("mem/..."))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for range failures {
+				got[i]++
+			}
+		})
+	}
+	wg.Wait()
+
+	// ASSERT
+	if want := []int{1, 0}; !slices.Equal(got, want) {
+		t.Errorf("got failure counts %v, want %v: the sessions must not share their rule configuration", got, want)
+	}
+}
+
+func TestReviveNewDoesNotModifyConfig(t *testing.T) {
+	conf, err := config.GetConfig("../defaults.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := len(conf.Rules)
+
+	_, err = revivelib.New(conf, true, 0, revivelib.NewExtraRule(&mockRule{}, lint.RuleConfig{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if conf.ErrorCode != 0 || conf.WarningCode != 0 {
+		t.Errorf("expected the exit codes of the config to be left unset, got %d and %d", conf.ErrorCode, conf.WarningCode)
+	}
+	if len(conf.Rules) != rules {
+		t.Errorf("expected the config to keep its %d rules, got %d", rules, len(conf.Rules))
+	}
+}
+
+func TestReviveConfigReaderWithConfig(t *testing.T) {
+	conf, err := config.GetConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = revivelib.NewWithOptions(conf, false, 0, revivelib.WithConfigReader(strings.NewReader("")))
+	if err == nil {
+		t.Fatal("expected an error when setting both a config and a config reader")
+	}
+}
+
 type mockRule struct{}
 
 func (*mockRule) Name() string {
@@ -0,0 +1,90 @@
+package revivelib
+
+import (
+	"go/token"
+
+	"github.com/mgechev/revive/lint"
+)
+
+// collapseOverlappingFailures forwards the failures once all of them are reported, except those of a rule
+// overlapping a failure of a rule declared in the OverlappingRules of the configuration as reporting the same findings.
+// The failures not forwarded are passed to onCollapse, if not nil, with the failure winning over them.
+func (r *Revive) collapseOverlappingFailures(failures <-chan lint.Failure, onCollapse func(CollapsedFailure)) <-chan lint.Failure {
+	// winners maps the overlapped rules to the rules whose failures win over theirs.
+	winners := map[string][]string{}
+	for winner, overlapped := range r.config.OverlappingRules {
+		for _, rule := range overlapped {
+			winners[rule] = append(winners[rule], winner)
+		}
+	}
+
+	result := make(chan lint.Failure)
+	go func() {
+		defer close(result)
+
+		var all []lint.Failure
+		byFileAndRule := map[string]map[string][]lint.Failure{}
+		for failure := range failures {
+			all = append(all, failure)
+			if failure.IsInternal() {
+				continue
+			}
+			byRule, ok := byFileAndRule[failure.Filename()]
+			if !ok {
+				byRule = map[string][]lint.Failure{}
+				byFileAndRule[failure.Filename()] = byRule
+			}
+			byRule[failure.RuleName] = append(byRule[failure.RuleName], failure)
+		}
+
+		for _, failure := range all {
+			if !failure.IsInternal() {
+				if winner, ok := r.overlappingWinner(failure, winners[failure.RuleName], byFileAndRule[failure.Filename()]); ok {
+					r.logger.Info("Overlapping failure collapsed",
+						"rule", failure.RuleName,
+						"winner", winner.RuleName,
+						"file", failure.Filename(),
+						"line", failure.Position.Start.Line,
+						"failure", failure.Failure,
+					)
+					if onCollapse != nil {
+						onCollapse(CollapsedFailure{Failure: failure, Winner: winner})
+					}
+					continue
+				}
+			}
+			result <- failure
+		}
+	}()
+	return result
+}
+
+// overlappingWinner returns the failure, among those of the winner rules, overlapping the failure.
+func (*Revive) overlappingWinner(failure lint.Failure, winners []string, byRule map[string][]lint.Failure) (lint.Failure, bool) {
+	for _, winner := range winners {
+		for _, other := range byRule[winner] {
+			if overlap(failure.Position, other.Position) {
+				return other, true
+			}
+		}
+	}
+	return lint.Failure{}, false
+}
+
+// overlap reports whether the ranges of two failures in the same file overlap.
+// A range without end is considered to end where it starts.
+func overlap(a, b lint.FailurePosition) bool {
+	return !before(end(a), b.Start) && !before(end(b), a.Start)
+}
+
+func end(p lint.FailurePosition) token.Position {
+	if p.End.Line == 0 {
+		return p.Start
+	}
+	return p.End
+}
+
+// before reports whether the position a is strictly before b.
+func before(a, b token.Position) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+}
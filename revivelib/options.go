@@ -0,0 +1,91 @@
+package revivelib
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+
+	"github.com/mgechev/revive/config"
+	"github.com/mgechev/revive/lint"
+)
+
+// Option configures a [Revive] instance created with [NewWithOptions].
+// An [ExtraRule] is an option adding the rule.
+type Option interface {
+	apply(r *Revive) error
+}
+
+type optionFunc func(r *Revive) error
+
+func (f optionFunc) apply(r *Revive) error {
+	return f(r)
+}
+
+func (e ExtraRule) apply(r *Revive) error {
+	r.extraRules = append(r.extraRules, e)
+	return nil
+}
+
+// PackageResolver resolves the include and exclude patterns of a lint into the packages to lint,
+// each as the list of its files.
+type PackageResolver func(includePatterns, excludePatterns []string) ([][]string, error)
+
+// WithLogger sets the logger of revive, instead of the one configured by the REVIVE_LOG_LEVEL environment variable.
+func WithLogger(logger *slog.Logger) Option {
+	return optionFunc(func(r *Revive) error {
+		r.logger = logger
+		return nil
+	})
+}
+
+// WithConfigReader sets the configuration of revive to the one read from reader,
+// in the TOML format of the configuration file. The configuration passed to [NewWithOptions] must then be nil.
+func WithConfigReader(reader io.Reader) Option {
+	return optionFunc(func(r *Revive) error {
+		if r.config != nil {
+			return errors.New("a configuration is already set, it cannot be read too")
+		}
+		conf, err := config.ReadConfig(reader)
+		if err != nil {
+			return err
+		}
+		r.config = conf
+		return nil
+	})
+}
+
+// WithFileReader sets how the linted files are read, instead of from disk.
+// The files passed to [Revive.LintWithOverlay] and [Revive.LintSources] are still read from memory.
+func WithFileReader(reader lint.ReadFile) Option {
+	return optionFunc(func(r *Revive) error {
+		r.readFile = reader
+		return nil
+	})
+}
+
+// WithPackageResolver sets how the packages to lint are resolved from the patterns passed to [Revive.Lint]
+// and [Revive.LintWithOverlay], instead of from the files on disk.
+func WithPackageResolver(resolver PackageResolver) Option {
+	return optionFunc(func(r *Revive) error {
+		r.resolvePackages = resolver
+		return nil
+	})
+}
+
+// WithSortedOutput sets whether the failures are sorted by file, line, column and rule
+// for the formatters, except those streaming their output. It is enabled by default.
+func WithSortedOutput(sorted bool) Option {
+	return optionFunc(func(r *Revive) error {
+		r.sortOutput = sorted
+		return nil
+	})
+}
+
+// WithStableOutput sets whether the failures are sorted for all the formatters, including those streaming
+// their output, which then write it once all the failures are reported.
+func WithStableOutput(stable bool) Option {
+	return optionFunc(func(r *Revive) error {
+		r.stableOutput = stable
+		return nil
+	})
+}
@@ -0,0 +1,56 @@
+package revivelib
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mgechev/revive/config"
+	"github.com/mgechev/revive/lint"
+)
+
+// FormatterOutput configures a formatter and the destination of its output.
+type FormatterOutput struct {
+	// Name is the name of the formatter.
+	Name string
+	// Arguments are the arguments used to configure the formatter.
+	Arguments lint.FormatterArguments
+	// Writer receives the output of the formatter.
+	Writer io.Writer
+}
+
+// FormatToOutputs fans out a given failures channel from [Revive.Lint] to every formatter
+// in outputs, and writes each formatted result, followed by a newline, to the output's writer.
+// Formatters implementing [lint.StreamingFormatter] write each failure as soon as it is received.
+//
+// The exit code is computed once over all failures.
+func (r *Revive) FormatToOutputs(
+	outputs []FormatterOutput,
+	failuresChan <-chan lint.Failure,
+) (exitCode int, err error) {
+	formatters := make([]lint.Formatter, len(outputs))
+	writers := make([]io.Writer, len(outputs))
+	for i, output := range outputs {
+		writers[i] = output.Writer
+		formatter, err := config.NewFormatter(output.Name, output.Arguments)
+		if err != nil {
+			return 0, fmt.Errorf("formatting - getting formatter: %w", err)
+		}
+		formatters[i] = formatter
+	}
+
+	formatted, exitCode, err := r.format(formatters, writers, failuresChan)
+	if err != nil {
+		return exitCode, err
+	}
+
+	for i, out := range formatted {
+		if out == "" {
+			continue
+		}
+		if _, err := fmt.Fprintln(outputs[i].Writer, out); err != nil {
+			return exitCode, fmt.Errorf("writing output of %s: %w", formatters[i].Name(), err)
+		}
+	}
+
+	return exitCode, nil
+}
@@ -0,0 +1,112 @@
+package revivelib
+
+import (
+	"cmp"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/mgechev/revive/lint"
+)
+
+// overlayReader returns a reader that reads the files in overlay from memory and the others with readFile.
+func overlayReader(overlay map[string][]byte, readFile lint.ReadFile) lint.ReadFile {
+	absOverlay := make(map[string][]byte, len(overlay))
+	for name, content := range overlay {
+		absOverlay[absPath(name)] = content
+	}
+
+	return func(file string) ([]byte, error) {
+		if content, ok := absOverlay[absPath(file)]; ok {
+			return content, nil
+		}
+
+		contents, err := readFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading file %v: %w", file, err)
+		}
+
+		return contents, nil
+	}
+}
+
+// addOverlayFiles adds the overlay files missing from packages to the package of their directory,
+// or to new packages if there is none.
+func addOverlayFiles(packages [][]string, overlay map[string][]byte) [][]string {
+	if len(overlay) == 0 {
+		return packages
+	}
+
+	known := map[string]bool{}
+	pkgByDir := map[string]int{}
+	for i, files := range packages {
+		for _, file := range files {
+			known[absPath(file)] = true
+		}
+		if len(files) > 0 {
+			pkgByDir[absPath(filepath.Dir(files[0]))] = i
+		}
+	}
+
+	missing := map[string][]byte{}
+	for name, content := range overlay {
+		if known[absPath(name)] {
+			continue
+		}
+		if _, err := os.Stat(name); err == nil {
+			continue // replaces a file on disk that is not linted
+		}
+		missing[name] = content
+	}
+
+	for _, files := range groupSources(missing) {
+		if i, ok := pkgByDir[absPath(filepath.Dir(files[0]))]; ok {
+			packages[i] = append(packages[i], files...)
+			continue
+		}
+		packages = append(packages, files)
+	}
+
+	return packages
+}
+
+// groupSources groups source files into packages by directory and package clause.
+// Files whose package clause can't be parsed are grouped by directory only,
+// so that they are reported as invalid files when linted.
+func groupSources(sources map[string][]byte) [][]string {
+	type pkgKey struct {
+		dir  string
+		name string
+	}
+
+	fset := token.NewFileSet()
+	groups := map[pkgKey][]string{}
+	for _, name := range slices.Sorted(maps.Keys(sources)) {
+		key := pkgKey{dir: absPath(filepath.Dir(name))}
+		if f, err := parser.ParseFile(fset, name, sources[name], parser.PackageClauseOnly); err == nil {
+			key.name = f.Name.Name
+		}
+		groups[key] = append(groups[key], name)
+	}
+
+	keys := slices.SortedFunc(maps.Keys(groups), func(a, b pkgKey) int {
+		return cmp.Or(cmp.Compare(a.dir, b.dir), cmp.Compare(a.name, b.name))
+	})
+	packages := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		packages = append(packages, groups[key])
+	}
+	return packages
+}
+
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return abs
+}
@@ -0,0 +1,109 @@
+package revivelib
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/mgechev/revive/lint"
+)
+
+// Result is the structured result of a lint.
+type Result struct {
+	// Failures are the failures reaching the confidence threshold,
+	// sorted by file, line, column and rule.
+	Failures []lint.Failure
+	// Severities counts the failures by severity.
+	Severities map[lint.Severity]int
+	// Rules counts the failures by rule name.
+	Rules map[string]int
+	// ExitCode is the exit code computed from the failures, as by [Revive.Format].
+	ExitCode int
+
+	// Packages are the files of the packages to lint, as given to the linter.
+	Packages [][]string
+	// Files are the files the rules were applied to, sorted.
+	Files []string
+	// GeneratedFiles are the files skipped because they are generated, sorted.
+	GeneratedFiles []string
+	// InvalidFiles are the files skipped because they cannot be parsed, sorted.
+	// Each of them is also reported by a failure.
+	InvalidFiles []string
+
+	// Collapsed are the failures not reported because they overlap a failure of a rule declared
+	// in the OverlappingRules of the configuration as reporting the same findings, sorted as the failures.
+	Collapsed []CollapsedFailure
+
+	// Diagnostics are the internal failures, such as rule crashes or rules running out of time,
+	// in the order they were reported.
+	Diagnostics []lint.Failure
+}
+
+// CollapsedFailure is a failure not reported because it overlaps the failure of a rule reporting the same findings.
+type CollapsedFailure struct {
+	// Failure is the failure not reported.
+	Failure lint.Failure
+	// Winner is the reported failure it overlaps, whose RuleName is the rule that won.
+	Winner lint.Failure
+}
+
+// LintResult lints the included patterns, skipping excluded ones, with the overlay files
+// as [Revive.LintWithOverlay] does, and returns the result once the lint is complete.
+// The overlay can be nil to lint the files on disk only.
+func (r *Revive) LintResult(ctx context.Context, overlay map[string][]byte, patterns ...*LintPattern) (*Result, error) {
+	packages, err := r.packages(overlay, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	coverage := lint.NewCoverage()
+	var collapsed []CollapsedFailure
+	onCollapse := func(c CollapsedFailure) { collapsed = append(collapsed, c) }
+	failures, err := r.lint(ctx, packages, overlay, coverage, onCollapse)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{
+		Severities: map[lint.Severity]int{},
+		Rules:      map[string]int{},
+		Packages:   packages,
+	}
+	for failure := range failures {
+		if failure.IsInternal() {
+			result.Diagnostics = append(result.Diagnostics, failure)
+			continue
+		}
+		if failure.Confidence < r.config.Confidence {
+			continue
+		}
+
+		result.Failures = append(result.Failures, failure)
+		result.Severities[r.severity(failure)]++
+		result.Rules[failure.RuleName]++
+		result.ExitCode = r.exitCode(result.ExitCode, failure)
+	}
+
+	slices.SortStableFunc(result.Failures, compareFailures)
+	// The failures are collapsed before the channel is closed.
+	result.Collapsed = collapsed
+	slices.SortStableFunc(result.Collapsed, func(a, b CollapsedFailure) int {
+		return compareFailures(a.Failure, b.Failure)
+	})
+	result.Files = coverage.Linted()
+	result.GeneratedFiles = coverage.Generated()
+	result.InvalidFiles = coverage.Invalid()
+
+	return result, nil
+}
+
+// compareFailures orders failures by file, line, column and rule, then by message.
+func compareFailures(a, b lint.Failure) int {
+	return cmp.Or(
+		cmp.Compare(a.Filename(), b.Filename()),
+		cmp.Compare(a.Position.Start.Line, b.Position.Start.Line),
+		cmp.Compare(a.Position.Start.Column, b.Position.Start.Column),
+		cmp.Compare(a.RuleName, b.RuleName),
+		cmp.Compare(a.Failure, b.Failure),
+	)
+}
@@ -3,6 +3,7 @@ package rule
 import (
 	"fmt"
 	"go/ast"
+	"slices"
 	"strings"
 	"sync"
 
@@ -42,16 +43,23 @@ func (ps *packages) methodNames(lp *lint.Package) pkgMethods {
 	return pkgm
 }
 
-var allPkgs = packages{pkgs: make([]pkgMethods, 1)}
+func (ps *packages) release(lp *lint.Package) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	ps.pkgs = slices.DeleteFunc(ps.pkgs, func(pkg pkgMethods) bool { return pkg.pkg == lp })
+}
 
 // ConfusingNamingRule lints method names that differ only by capitalization.
-type ConfusingNamingRule struct{}
+type ConfusingNamingRule struct {
+	pkgs packages
+}
 
 // Apply applies the rule to given file.
-func (*ConfusingNamingRule) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
+func (r *ConfusingNamingRule) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
 	var failures []lint.Failure
 	fileAst := file.AST
-	pkgm := allPkgs.methodNames(file.Pkg)
+	pkgm := r.pkgs.methodNames(file.Pkg)
 	walker := lintConfusingNames{
 		fileName: file.Name,
 		pkgm:     pkgm,
@@ -70,6 +78,13 @@ func (*ConfusingNamingRule) Name() string {
 	return "confusing-naming"
 }
 
+// ReleasePackage forgets the method names of the package once its linting ended.
+//
+// ReleasePackage implements the [lint.PackageStateRule] interface.
+func (r *ConfusingNamingRule) ReleasePackage(pkg *lint.Package) {
+	r.pkgs.release(pkg)
+}
+
 // checkMethodName checks if a given method/function name is similar (just case differences) to other method/function
 // of the same struct/file.
 func checkMethodName(holder string, id *ast.Ident, w *lintConfusingNames) {
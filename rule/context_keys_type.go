@@ -13,22 +13,25 @@ import (
 type ContextKeysType struct{}
 
 // Apply applies the rule to given file.
-func (*ContextKeysType) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
-	var failures []lint.Failure
+func (r *ContextKeysType) Apply(file *lint.File, arguments lint.Arguments) []lint.Failure {
+	return lint.ApplyNodeRule(r, file, arguments)
+}
+
+// NodeTypes returns the types of the nodes checked by the rule.
+func (*ContextKeysType) NodeTypes() []ast.Node {
+	return []ast.Node{(*ast.CallExpr)(nil)}
+}
 
-	fileAst := file.AST
+// CheckNodes returns the function checking the nodes of the file.
+func (*ContextKeysType) CheckNodes(file *lint.File, _ lint.Arguments, onFailure func(lint.Failure)) func(ast.Node) {
 	walker := lintContextKeyTypes{
-		file:    file,
-		fileAst: fileAst,
-		onFailure: func(failure lint.Failure) {
-			failures = append(failures, failure)
-		},
+		file:      file,
+		fileAst:   file.AST,
+		onFailure: onFailure,
 	}
 
 	file.Pkg.TypeCheck()
-	ast.Walk(walker, fileAst)
-
-	return failures
+	return walker.check
 }
 
 // Name returns the rule name.
@@ -42,12 +45,8 @@ type lintContextKeyTypes struct {
 	onFailure func(lint.Failure)
 }
 
-func (w lintContextKeyTypes) Visit(n ast.Node) ast.Visitor {
-	if n, ok := n.(*ast.CallExpr); ok {
-		checkContextKeyType(w, n)
-	}
-
-	return w
+func (w lintContextKeyTypes) check(n ast.Node) {
+	checkContextKeyType(w, n.(*ast.CallExpr))
 }
 
 func checkContextKeyType(w lintContextKeyTypes, x *ast.CallExpr) {
@@ -0,0 +1,278 @@
+package rule
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/mgechev/revive/lint"
+)
+
+// ContextPropagationRule spots contexts that are not propagated: new root contexts created, and calls
+// to functions that have a variant taking a context, in functions receiving a context,
+// and derived contexts whose cancel function is never called.
+type ContextPropagationRule struct{}
+
+// Apply applies the rule to given file.
+func (*ContextPropagationRule) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
+	var failures []lint.Failure
+
+	walker := lintContextPropagation{
+		file: file,
+		onFailure: func(failure lint.Failure) {
+			failures = append(failures, failure)
+		},
+	}
+
+	file.Pkg.TypeCheck()
+	ast.Walk(walker, file.AST)
+
+	return failures
+}
+
+// Name returns the rule name.
+func (*ContextPropagationRule) Name() string {
+	return "context-propagation"
+}
+
+// contextVariantSuffixes are the suffixes of the names of the variants of functions taking a context.
+var contextVariantSuffixes = []string{"Context", "Ctx", "WithContext"}
+
+// cancelableContextFuncs are the functions of the context package returning a cancel function as second result.
+var cancelableContextFuncs = map[string]bool{
+	"context.WithCancel":        true,
+	"context.WithCancelCause":   true,
+	"context.WithDeadline":      true,
+	"context.WithDeadlineCause": true,
+	"context.WithTimeout":       true,
+	"context.WithTimeoutCause":  true,
+}
+
+type lintContextPropagation struct {
+	file      *lint.File
+	onFailure func(lint.Failure)
+	// ctxName is the name of the context received by the enclosing function, if any.
+	ctxName string
+	// body is the body of the outermost enclosing function, including the function literals it contains.
+	body *ast.BlockStmt
+}
+
+func (w lintContextPropagation) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		w.ctxName, w.body = w.contextParam(n.Type), n.Body
+		return w
+	case *ast.FuncLit:
+		// a function literal can use the context of the enclosing function
+		if name := w.contextParam(n.Type); name != "" {
+			w.ctxName = name
+		}
+		if w.body == nil {
+			w.body = n.Body // i.e. a function literal assigned to a package variable
+		}
+		return w
+	case *ast.CallExpr:
+		w.checkCall(n)
+	case *ast.AssignStmt:
+		if len(n.Lhs) == 2 && len(n.Rhs) == 1 {
+			w.checkCancel(n.Lhs[1], n.Rhs[0])
+		}
+	case *ast.ValueSpec:
+		if len(n.Names) == 2 && len(n.Values) == 1 {
+			w.checkCancel(n.Names[1], n.Values[0])
+		}
+	}
+	return w
+}
+
+// contextParam returns the name of the first parameter of type context.Context, or an empty string.
+func (w lintContextPropagation) contextParam(fn *ast.FuncType) string {
+	for _, field := range fn.Params.List {
+		if !isContextType(w.file.Pkg.TypeOf(field.Type)) {
+			continue
+		}
+		for _, name := range field.Names {
+			if name.Name != "_" {
+				return name.Name
+			}
+		}
+	}
+	return ""
+}
+
+func isContextType(typ types.Type) bool {
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+func (w lintContextPropagation) callee(call *ast.CallExpr) *types.Func {
+	fn, _ := typeutil.Callee(w.file.Pkg.TypesInfo(), call).(*types.Func)
+	return fn
+}
+
+func (w lintContextPropagation) checkCall(call *ast.CallExpr) {
+	fn := w.callee(call)
+	if fn == nil {
+		return
+	}
+
+	switch name := fn.FullName(); {
+	case name == "context.Background" || name == "context.TODO":
+		if w.ctxName == "" {
+			return
+		}
+		w.onFailure(lint.Failure{
+			Category:        lint.FailureCategoryBadPractice,
+			Confidence:      1,
+			Node:            call,
+			Failure:         fmt.Sprintf("%s() creates a new context in a function receiving the context %s, use %s instead", name, w.ctxName, w.ctxName),
+			ReplacementLine: replacementLine(w.file, call, w.ctxName),
+		})
+	case w.ctxName != "":
+		variant := w.contextVariant(call, fn)
+		if variant == "" {
+			return
+		}
+		w.onFailure(lint.Failure{
+			Category:   lint.FailureCategoryBadPractice,
+			Confidence: 0.8,
+			Node:       call,
+			Failure:    fmt.Sprintf("call to %s in a function receiving the context %s, use %s to pass it", w.file.Render(call.Fun), w.ctxName, variant),
+		})
+	}
+}
+
+// contextVariant returns the expression calling the variant of the called function taking a context
+// as first parameter, such as db.QueryContext for db.Query, or an empty string if there is no such variant.
+func (w lintContextPropagation) contextVariant(call *ast.CallExpr, fn *types.Func) string {
+	if takesContext(fn) {
+		return ""
+	}
+
+	for _, suffix := range contextVariantSuffixes {
+		name := fn.Name() + suffix
+
+		var variant types.Object
+		sel, isSelector := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if fn.Signature().Recv() != nil {
+			if !isSelector {
+				return ""
+			}
+			recv := w.file.Pkg.TypeOf(sel.X)
+			if recv == nil {
+				return ""
+			}
+			variant, _, _ = types.LookupFieldOrMethod(recv, true, fn.Pkg(), name)
+		} else if fn.Pkg() != nil {
+			variant = fn.Pkg().Scope().Lookup(name)
+		}
+
+		variantFunc, ok := variant.(*types.Func)
+		if !ok || !takesContext(variantFunc) {
+			continue
+		}
+		if isSelector {
+			return w.file.Render(sel.X) + "." + name
+		}
+		return name
+	}
+	return ""
+}
+
+// takesContext reports whether the first parameter of the function is a context.Context.
+func takesContext(fn *types.Func) bool {
+	params := fn.Signature().Params()
+	return params.Len() > 0 && isContextType(params.At(0).Type())
+}
+
+// checkCancel creates a failure if the cancel function returned by a call to context.WithCancel,
+// context.WithTimeout... is discarded or never used.
+func (w lintContextPropagation) checkCancel(cancel, value ast.Expr) {
+	call, ok := ast.Unparen(value).(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	fn := w.callee(call)
+	if fn == nil || !cancelableContextFuncs[fn.FullName()] {
+		return
+	}
+
+	id, ok := cancel.(*ast.Ident)
+	if !ok {
+		return // the cancel function is stored elsewhere
+	}
+
+	switch {
+	case id.Name == "_":
+		w.onFailure(lint.Failure{
+			Category:   lint.FailureCategoryBadPractice,
+			Confidence: 1,
+			Node:       call,
+			Failure:    fmt.Sprintf("the cancel function returned by %s is discarded, the context leaks until its parent is canceled", fn.FullName()),
+		})
+	case !w.isUsed(id):
+		w.onFailure(lint.Failure{
+			Category:   lint.FailureCategoryBadPractice,
+			Confidence: 1,
+			Node:       call,
+			Failure:    fmt.Sprintf("the cancel function %s returned by %s is never called, the context leaks until its parent is canceled", id.Name, fn.FullName()),
+		})
+	}
+}
+
+// isUsed reports whether the variable assigned by id is used in the enclosing function: called, deferred,
+// returned or passed elsewhere. Uses before the assignment are ignored, unless they are in a loop with the assignment
+// or in a function literal, such as a deferred closure calling the cancel function if it is set.
+func (w lintContextPropagation) isUsed(id *ast.Ident) bool {
+	info := w.file.Pkg.TypesInfo()
+	obj := info.ObjectOf(id)
+	if obj == nil || w.body == nil {
+		return true // unable to track the variable
+	}
+
+	from := id.Pos()
+	ast.Inspect(w.body, func(node ast.Node) bool {
+		switch node.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			if node.Pos() <= id.Pos() && id.End() <= node.End() {
+				from = node.Pos()
+				return false // the outermost loop
+			}
+		}
+		return node == nil || node.Pos() <= id.Pos() && id.End() <= node.End()
+	})
+
+	used := false
+	assigned := map[*ast.Ident]bool{} // assigning the variable again does not use it
+	var inspect func(node ast.Node, inFuncLit bool)
+	inspect = func(node ast.Node, inFuncLit bool) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				for _, lhs := range n.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						assigned[ident] = true
+					}
+				}
+			case *ast.FuncLit:
+				// a function literal not containing the assignment can be called after it
+				if !inFuncLit && (id.Pos() < n.Pos() || n.End() < id.End()) {
+					inspect(n.Body, true)
+					return false
+				}
+			case *ast.Ident:
+				if n != id && !assigned[n] && (inFuncLit || n.Pos() >= from) && info.Uses[n] == obj {
+					used = true
+				}
+			}
+			return !used
+		})
+	}
+	inspect(w.body, false)
+	return used
+}
@@ -0,0 +1,363 @@
+package rule
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/mgechev/revive/lint"
+)
+
+// ErrorWrappingRule spots errors formatted without being wrapped, and errors compared or
+// type-asserted without [errors.Is] or [errors.As], missing the errors they wrap.
+type ErrorWrappingRule struct{}
+
+// Apply applies the rule to given file.
+func (*ErrorWrappingRule) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
+	if !file.Pkg.IsAtLeastGoVersion(lint.Go113) {
+		return nil // errors cannot be wrapped before Go 1.13
+	}
+
+	var failures []lint.Failure
+
+	walker := lintErrorWrapping{
+		file:          file,
+		importsErrors: importsErrors(file.AST),
+		onFailure: func(failure lint.Failure) {
+			failures = append(failures, failure)
+		},
+	}
+
+	file.Pkg.TypeCheck()
+	ast.Walk(walker, file.AST)
+
+	return failures
+}
+
+// Name returns the rule name.
+func (*ErrorWrappingRule) Name() string {
+	return "error-wrapping"
+}
+
+type lintErrorWrapping struct {
+	file          *lint.File
+	importsErrors bool // whether the errors package can be used in replacements
+	onFailure     func(lint.Failure)
+}
+
+// importsErrors reports whether the file imports the errors package under its name.
+func importsErrors(file *ast.File) bool {
+	for _, imp := range file.Imports {
+		if imp.Path.Value == `"errors"` && (imp.Name == nil || imp.Name.Name == "errors") {
+			return true
+		}
+	}
+	return false
+}
+
+// unwrappedErrors are the errors documented as returned unwrapped, and thus compared with == by convention.
+var unwrappedErrors = map[string]bool{
+	"io.EOF":              true,
+	"io.ErrUnexpectedEOF": true,
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+func (w lintErrorWrapping) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if w.isErrorMethod(n) {
+			return nil // Is and As methods compare and assert the errors themselves
+		}
+	case *ast.CallExpr:
+		w.checkErrorf(n)
+	case *ast.BinaryExpr:
+		w.checkComparison(n)
+	case *ast.TypeAssertExpr:
+		if n.Type != nil && w.isError(n.X) {
+			w.addFailure(n, fmt.Sprintf("type assertion on error %s fails on wrapped errors, use errors.As", w.file.Render(n.X)), "")
+		}
+	case *ast.TypeSwitchStmt:
+		if x := typeSwitchOperand(n); x != nil && w.isError(x) {
+			w.addFailure(n, fmt.Sprintf("type switch on error %s fails on wrapped errors, use errors.As", w.file.Render(x)), "")
+		}
+	}
+	return w
+}
+
+// isErrorMethod reports whether the function is the Is or As method of an error type.
+func (lintErrorWrapping) isErrorMethod(fn *ast.FuncDecl) bool {
+	if fn.Recv == nil || fn.Name.Name != "Is" && fn.Name.Name != "As" {
+		return false
+	}
+	return fn.Type.Params.NumFields() == 1 && fn.Type.Results.NumFields() == 1
+}
+
+// isError reports whether the expression implements the error interface. Untyped nil does not.
+func (w lintErrorWrapping) isError(expr ast.Expr) bool {
+	typ := w.file.Pkg.TypeOf(expr)
+	return typ != nil && hasMethod(typ, "Error", types.Typ[types.String])
+}
+
+// hasMethod reports whether the type, or a pointer to it, has a method with the given name, without parameters,
+// and with the given results. Unlike [types.Implements], it does not report methods found in types
+// that are unknown, such as types embedded from a package that failed to import.
+func hasMethod(typ types.Type, name string, results ...types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(typ, true, nil, name)
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := fn.Signature()
+	if sig.Params().Len() != 0 || sig.Results().Len() != len(results) {
+		return false
+	}
+	for i, result := range results {
+		if !types.Identical(sig.Results().At(i).Type(), result) {
+			return false
+		}
+	}
+	return true
+}
+
+// isErrorInterface reports whether the expression is of the error interface type.
+func (w lintErrorWrapping) isErrorInterface(expr ast.Expr) bool {
+	typ := w.file.Pkg.TypeOf(expr)
+	return typ != nil && types.Identical(typ, errorType)
+}
+
+func (w lintErrorWrapping) checkComparison(expr *ast.BinaryExpr) {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return
+	}
+	if !w.isError(expr.X) || !w.isError(expr.Y) {
+		return // not comparing errors, or comparing an error to nil
+	}
+	if !w.isErrorInterface(expr.X) && !w.isErrorInterface(expr.Y) {
+		return // comparing values of concrete types
+	}
+	if w.isUnwrappedError(expr.X) || w.isUnwrappedError(expr.Y) {
+		return // i.e. io.Reader implementations return io.EOF itself
+	}
+
+	replacement := fmt.Sprintf("errors.Is(%s, %s)", w.file.Render(expr.X), w.file.Render(expr.Y))
+	if expr.Op == token.NEQ {
+		replacement = "!" + replacement
+	}
+	msg := fmt.Sprintf("comparing errors with %s fails on wrapped errors, use %s", expr.Op, replacement)
+	var line string
+	if w.importsErrors {
+		line = replacementLine(w.file, expr, replacement)
+	} else {
+		msg += " and import errors"
+	}
+	w.addFailure(expr, msg, line)
+}
+
+// isUnwrappedError reports whether the expression is one of the unwrappedErrors variables.
+func (w lintErrorWrapping) isUnwrappedError(expr ast.Expr) bool {
+	var id *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return false
+	}
+	v, ok := w.file.Pkg.TypesInfo().Uses[id].(*types.Var)
+	return ok && v.Pkg() != nil && unwrappedErrors[v.Pkg().Path()+"."+v.Name()]
+}
+
+func (w lintErrorWrapping) checkErrorf(call *ast.CallExpr) {
+	fn, ok := typeutil.Callee(w.file.Pkg.TypesInfo(), call).(*types.Func)
+	if !ok || fn.FullName() != "fmt.Errorf" || len(call.Args) == 0 || call.Ellipsis.IsValid() {
+		return
+	}
+
+	format, ok := w.constantString(call.Args[0])
+	if !ok {
+		return // format not known at compile time
+	}
+
+	verbs := formatVerbs(format)
+	wrapping := 0
+	for _, verb := range verbs {
+		if verb.verb == 'w' {
+			wrapping++
+		}
+	}
+
+	canWrapSeveral := w.file.Pkg.IsAtLeastGoVersion(lint.Go120)
+	if wrapping > 1 && !canWrapSeveral {
+		w.addFailure(call, "fmt.Errorf calls with several %w verbs require Go 1.20", "")
+		return
+	}
+	if wrapping > 0 && !canWrapSeveral {
+		return // the error cannot wrap more errors
+	}
+
+	args := call.Args[1:]
+	var unwrapped []formatVerb
+	for _, verb := range verbs {
+		if verb.verb == 'w' || verb.arg < 0 || verb.arg >= len(args) || !w.isError(args[verb.arg]) {
+			continue
+		}
+		unwrapped = append(unwrapped, verb)
+		if !canWrapSeveral {
+			break
+		}
+	}
+	if len(unwrapped) == 0 {
+		return
+	}
+
+	first := unwrapped[0]
+	msg := fmt.Sprintf("fmt.Errorf formats the error %s with %s, use %%w to wrap it so that errors.Is and errors.As can inspect it",
+		w.file.Render(args[first.arg]), format[first.start:first.end])
+	w.addFailure(call, msg, w.wrappingFormat(call.Args[0], format, unwrapped))
+}
+
+// constantString returns the value of the expression if it is a constant string.
+func (w lintErrorWrapping) constantString(expr ast.Expr) (string, bool) {
+	tv, ok := w.file.Pkg.TypesInfo().Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// wrappingFormat returns the line of the format string literal with the given verbs replaced by %w,
+// or an empty string if the format is not a string literal.
+func (w lintErrorWrapping) wrappingFormat(formatExpr ast.Expr, format string, verbs []formatVerb) string {
+	lit, ok := formatExpr.(*ast.BasicLit)
+	if !ok {
+		return ""
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, verb := range verbs {
+		if strings.Contains(format[verb.start:verb.end], "[") {
+			return "" // explicit argument indexes
+		}
+		b.WriteString(format[last:verb.start])
+		b.WriteString("%w")
+		last = verb.end
+	}
+	b.WriteString(format[last:])
+
+	replacement := strconv.Quote(b.String())
+	if strings.HasPrefix(lit.Value, "`") {
+		if strings.Contains(b.String(), "`") {
+			return ""
+		}
+		replacement = "`" + b.String() + "`"
+	}
+	return replacementLine(w.file, lit, replacement)
+}
+
+func (w lintErrorWrapping) addFailure(node ast.Node, msg, replacementLine string) {
+	w.onFailure(lint.Failure{
+		Category:        lint.FailureCategoryErrors,
+		Confidence:      1,
+		Node:            node,
+		Failure:         msg,
+		ReplacementLine: replacementLine,
+	})
+}
+
+// typeSwitchOperand returns the expression whose type is switched on.
+func typeSwitchOperand(stmt *ast.TypeSwitchStmt) ast.Expr {
+	var assert ast.Expr
+	switch s := stmt.Assign.(type) {
+	case *ast.ExprStmt:
+		assert = s.X
+	case *ast.AssignStmt:
+		if len(s.Rhs) == 1 {
+			assert = s.Rhs[0]
+		}
+	}
+
+	if x, ok := assert.(*ast.TypeAssertExpr); ok {
+		return x.X
+	}
+	return nil
+}
+
+// formatVerb is a verb of a format string.
+type formatVerb struct {
+	verb       rune
+	arg        int // the index of the formatted argument, after the format
+	start, end int // the offsets of the whole directive in the format, e.g. %+v
+}
+
+// formatVerbs parses the verbs of a fmt format string, following the argument indexes as fmt does.
+func formatVerbs(format string) []formatVerb {
+	var verbs []formatVerb
+	arg := 0
+	for i := 0; i < len(format); {
+		if format[i] != '%' {
+			i++
+			continue
+		}
+
+		start := i
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++
+		}
+
+		argIndex := func() {
+			if i >= len(format) || format[i] != '[' {
+				return
+			}
+			end := strings.IndexByte(format[i:], ']')
+			if end < 0 {
+				return
+			}
+			if n, err := strconv.Atoi(format[i+1 : i+end]); err == nil {
+				arg = n - 1
+			}
+			i += end + 1
+		}
+		number := func() {
+			argIndex()
+			if i < len(format) && format[i] == '*' {
+				arg++
+				i++
+				return
+			}
+			for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+				i++
+			}
+		}
+
+		number() // width
+		if i < len(format) && format[i] == '.' {
+			i++
+			number() // precision
+		}
+		argIndex()
+		if i >= len(format) {
+			break
+		}
+
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		i += size
+		if verb == '%' {
+			continue
+		}
+		verbs = append(verbs, formatVerb{verb: verb, arg: arg, start: start, end: i})
+		arg++
+	}
+	return verbs
+}
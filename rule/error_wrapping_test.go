@@ -0,0 +1,29 @@
+package rule
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFormatVerbs(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []formatVerb
+	}{
+		{format: "no verbs", want: nil},
+		{format: "100%% %v", want: []formatVerb{{verb: 'v', arg: 0, start: 6, end: 8}}},
+		{format: "%s: %+v", want: []formatVerb{{verb: 's', arg: 0, start: 0, end: 2}, {verb: 'v', arg: 1, start: 4, end: 7}}},
+		{format: "%*.*f %w", want: []formatVerb{{verb: 'f', arg: 2, start: 0, end: 5}, {verb: 'w', arg: 3, start: 6, end: 8}}},
+		{format: "%[2]v %[1]s %d", want: []formatVerb{{verb: 'v', arg: 1, start: 0, end: 5}, {verb: 's', arg: 0, start: 6, end: 11}, {verb: 'd', arg: 1, start: 12, end: 14}}},
+		{format: "%-10q|%é", want: []formatVerb{{verb: 'q', arg: 0, start: 0, end: 5}, {verb: 'é', arg: 1, start: 6, end: 9}}},
+		{format: "trailing %", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := formatVerbs(tt.format); !slices.Equal(got, tt.want) {
+				t.Errorf("formatVerbs(%q) = %+v, want %+v", tt.format, got, tt.want)
+			}
+		})
+	}
+}
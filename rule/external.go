@@ -0,0 +1,199 @@
+package rule
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mgechev/revive/lint"
+)
+
+// ExternalRule is a rule implemented by an external command.
+//
+// The command is run once per package: it receives an [ExternalRuleRequest] as JSON on its standard input,
+// and writes the failures it finds on its standard output, as a JSON array with the shape
+// of the output of the json formatter.
+type ExternalRule struct {
+	name    string
+	command []string
+
+	mu sync.Mutex
+	// results holds the failures found by the command in each package, until the linting of the package ends.
+	results map[*lint.Package]*externalRuleResult
+}
+
+// ExternalRuleRequest is the input sent to the command of an [ExternalRule].
+type ExternalRuleRequest struct {
+	// Files are the paths of the files of the package to lint.
+	Files []string `json:"files"`
+	// Sources are the contents of the files, keyed by path. They can differ from the contents on disk,
+	// e.g. for unsaved files linted through revivelib, so the command should read them rather than the files.
+	Sources map[string]string `json:"sources"`
+	// GoVersion is the Go version of the package.
+	GoVersion string `json:"goVersion"`
+	// Arguments are the arguments of the rule from the configuration.
+	Arguments lint.Arguments `json:"arguments"`
+}
+
+type externalRuleResult struct {
+	once     sync.Once
+	failures map[string][]lint.Failure
+	err      error
+}
+
+// externalFailure is a failure written by an external command.
+// Unlike in [lint.Failure], the confidence defaults to 1 when it is omitted.
+type externalFailure struct {
+	lint.Failure
+
+	Confidence *float64 `json:"Confidence"`
+}
+
+// NewExternalRule creates a rule with the given name, implemented by the given command line.
+func NewExternalRule(name string, command []string) (*ExternalRule, error) {
+	if len(command) == 0 || command[0] == "" {
+		return nil, fmt.Errorf("the external rule %s requires a command", name)
+	}
+
+	return &ExternalRule{
+		name:    name,
+		command: command,
+		results: map[*lint.Package]*externalRuleResult{},
+	}, nil
+}
+
+// Apply applies the rule to given file.
+func (r *ExternalRule) Apply(file *lint.File, arguments lint.Arguments) []lint.Failure {
+	result := r.result(file.Pkg)
+	result.once.Do(func() {
+		result.failures, result.err = r.run(file.Pkg, arguments)
+		if result.err == nil {
+			r.logStrayFailures(file, result.failures)
+		}
+	})
+
+	if result.err != nil {
+		return []lint.Failure{lint.NewInternalFailure(result.err.Error())}
+	}
+
+	return result.failures[filepath.Clean(file.Name)]
+}
+
+// Name returns the rule name.
+func (r *ExternalRule) Name() string {
+	return r.name
+}
+
+// result returns the result of the command for the package, to be computed by the first file asking for it.
+func (r *ExternalRule) result(pkg *lint.Package) *externalRuleResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result, ok := r.results[pkg]
+	if !ok {
+		result = &externalRuleResult{}
+		r.results[pkg] = result
+	}
+	return result
+}
+
+// ReleasePackage forgets the result of the command for the package once its linting ended.
+//
+// ReleasePackage implements the [lint.PackageStateRule] interface.
+func (r *ExternalRule) ReleasePackage(pkg *lint.Package) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.results, pkg)
+}
+
+// logStrayFailures logs the failures found by the command in files that are not part of the package,
+// as they cannot be reported.
+func (r *ExternalRule) logStrayFailures(file *lint.File, failures map[string][]lint.Failure) {
+	files := map[string]bool{}
+	for name := range file.Pkg.Files() {
+		files[filepath.Clean(name)] = true
+	}
+
+	for _, filename := range slices.Sorted(maps.Keys(failures)) {
+		if !files[filename] {
+			file.Logger().Warn("external rule reported failures in a file outside of the package, ignoring them",
+				"rule", r.name,
+				"file", filename,
+				"failures", len(failures[filename]),
+			)
+		}
+	}
+}
+
+// run runs the command on the package, and returns the failures it found keyed by file.
+func (r *ExternalRule) run(pkg *lint.Package, arguments lint.Arguments) (map[string][]lint.Failure, error) {
+	files := pkg.Files()
+	request := ExternalRuleRequest{
+		Files:     slices.Sorted(maps.Keys(files)),
+		Sources:   make(map[string]string, len(files)),
+		Arguments: arguments,
+	}
+	for name, file := range files {
+		request.Sources[name] = string(file.Content())
+	}
+	if v := pkg.GoVersion(); v != nil {
+		request.GoVersion = v.String()
+	}
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("external rule %s: encoding the request: %w", r.name, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(r.command[0], r.command[1:]...) //nolint:gosec // ignore G204: the command comes from the configuration
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("external rule %s: running %q: %w", r.name, strings.Join(r.command, " "), err)
+	}
+
+	failures, err := r.decodeFailures(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("external rule %s: decoding the output of %q: %w", r.name, strings.Join(r.command, " "), err)
+	}
+	return failures, nil
+}
+
+func (r *ExternalRule) decodeFailures(output []byte) (map[string][]lint.Failure, error) {
+	result := map[string][]lint.Failure{}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return result, nil
+	}
+
+	var decoded []externalFailure
+	if err := json.Unmarshal(output, &decoded); err != nil {
+		return nil, err
+	}
+
+	for _, ef := range decoded {
+		failure := ef.Failure
+		if failure.Failure == "" {
+			return nil, errors.New("missing failure message")
+		}
+		failure.RuleName = r.name
+		failure.Confidence = 1
+		if ef.Confidence != nil {
+			failure.Confidence = *ef.Confidence
+		}
+		filename := filepath.Clean(failure.Filename())
+		result[filename] = append(result[filename], failure)
+	}
+	return result, nil
+}
@@ -0,0 +1,498 @@
+package rule
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/mgechev/revive/lint"
+)
+
+// MutexUsageRule spots misuses of locks: locks not released on all the paths of the function acquiring them,
+// unlocks deferred before locking or in loops, read locks released as write locks and vice versa,
+// and values containing a lock, such as a [sync.Mutex], copied.
+type MutexUsageRule struct{}
+
+// Apply applies the rule to given file.
+func (*MutexUsageRule) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
+	var failures []lint.Failure
+
+	walker := lintMutexUsage{
+		file:     file,
+		reported: map[token.Pos]bool{},
+		onFailure: func(failure lint.Failure) {
+			failures = append(failures, failure)
+		},
+	}
+
+	file.Pkg.TypeCheck()
+	ast.Walk(walker, file.AST)
+
+	return failures
+}
+
+// Name returns the rule name.
+func (*MutexUsageRule) Name() string {
+	return "mutex-usage"
+}
+
+// lockerTypes are the receiver types of the methods acquiring and releasing locks, as given by [types.TypeString].
+var lockerTypes = map[string]bool{
+	"*sync.Mutex":   true,
+	"*sync.RWMutex": true,
+	"sync.Locker":   true,
+}
+
+// unlockMethods maps the methods acquiring a lock to the methods releasing it.
+var unlockMethods = map[string]string{
+	"Lock":  "Unlock",
+	"RLock": "RUnlock",
+}
+
+// lockTypes are the types of the sync package that must not be copied after first use.
+var lockTypes = map[string]bool{
+	"Mutex":   true,
+	"RWMutex": true,
+	"Once":    true,
+	"Cond":    true,
+}
+
+type lintMutexUsage struct {
+	file      *lint.File
+	onFailure func(lint.Failure)
+	// reported are the positions of the unlock calls already reported as mismatched.
+	reported map[token.Pos]bool
+}
+
+// lockScope is the part of a function whose statements are checked.
+type lockScope struct {
+	body    *ast.BlockStmt // the body of the function
+	results *ast.FieldList // the results of the function
+	loop    *ast.BlockStmt // the body of the innermost loop, if any
+}
+
+// lockCall is a call acquiring or releasing a lock.
+type lockCall struct {
+	call   *ast.CallExpr
+	key    string // the expression of the lock, e.g. s.mu
+	method string
+}
+
+func (c lockCall) String() string {
+	return c.key + "." + c.method + "()"
+}
+
+func (w lintMutexUsage) Visit(node ast.Node) ast.Visitor {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		if n.Recv != nil {
+			w.checkParams(n.Recv, "receiver", "use a pointer receiver")
+		}
+		w.checkParams(n.Type.Params, "parameter", "use a pointer")
+		if n.Body != nil {
+			w.checkLocks(n.Body.List, nil, lockScope{body: n.Body, results: n.Type.Results})
+		}
+	case *ast.FuncLit:
+		w.checkParams(n.Type.Params, "parameter", "use a pointer")
+		w.checkLocks(n.Body.List, nil, lockScope{body: n.Body, results: n.Type.Results})
+	case *ast.RangeStmt:
+		w.checkRangeValue(n)
+	case *ast.CompositeLit:
+		w.checkCopiedElts(n)
+	}
+	return w
+}
+
+// lockCall returns the call if it acquires or releases a lock.
+func (w lintMutexUsage) lockCall(call *ast.CallExpr) (lockCall, bool) {
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return lockCall{}, false
+	}
+	fn, ok := typeutil.Callee(w.file.Pkg.TypesInfo(), call).(*types.Func)
+	if !ok || fn.Signature().Recv() == nil || !lockerTypes[types.TypeString(fn.Signature().Recv().Type(), nil)] {
+		return lockCall{}, false
+	}
+	if _, isLock := unlockMethods[fn.Name()]; !isLock && !isUnlock(fn.Name()) {
+		return lockCall{}, false
+	}
+	return lockCall{call: call, key: w.file.Render(sel.X), method: fn.Name()}, true
+}
+
+func isUnlock(method string) bool {
+	return method == "Unlock" || method == "RUnlock"
+}
+
+// stmtLockCall returns the call acquiring or releasing a lock made, or deferred, by the statement.
+func (w lintMutexUsage) stmtLockCall(stmt ast.Stmt) (c lockCall, deferred bool) {
+	switch s := stmt.(type) {
+	case *ast.ExprStmt:
+		if call, ok := ast.Unparen(s.X).(*ast.CallExpr); ok {
+			c, _ = w.lockCall(call)
+		}
+	case *ast.DeferStmt:
+		c, _ = w.lockCall(s.Call)
+		deferred = true
+	}
+	return c, deferred
+}
+
+// checkLocks checks the locks acquired by the statements of a list are released on all the paths
+// of the function. The paths reaching the end of the list follow with the statements after the enclosing
+// statements, given from the innermost, up to the end of the function or of the enclosing loop.
+func (w lintMutexUsage) checkLocks(stmts []ast.Stmt, outer [][]ast.Stmt, scope lockScope) {
+	for i, stmt := range stmts {
+		w.checkNestedLocks(stmt, append([][]ast.Stmt{stmts[i+1:]}, outer...), scope)
+
+		c, deferred := w.stmtLockCall(stmt)
+		switch {
+		case c.call == nil:
+		case deferred && isUnlock(c.method):
+			w.checkDeferredUnlock(c, stmts[:i], stmts[i+1:])
+			if scope.loop != nil && w.reachesEnd(stmts[i+1:], outer) {
+				w.addFailure(stmt, 1, fmt.Sprintf("defer %s in a loop runs when the function returns, not at the end of the iteration", c))
+			}
+		case !deferred && !isUnlock(c.method):
+			if w.deferredUnlock(scope.body, c) || w.heldOnEntry(scope.body, c.key) {
+				continue // already released by a deferred call, or released and acquired again for the caller
+			}
+			w.checkUnlocked(c, stmts[i+1:], outer, scope)
+		}
+	}
+}
+
+// checkNestedLocks checks the locks acquired in the blocks of a statement.
+func (w lintMutexUsage) checkNestedLocks(stmt ast.Stmt, outer [][]ast.Stmt, scope lockScope) {
+	switch s := stmt.(type) {
+	case *ast.BlockStmt:
+		w.checkLocks(s.List, outer, scope)
+	case *ast.LabeledStmt:
+		w.checkNestedLocks(s.Stmt, outer, scope)
+	case *ast.IfStmt:
+		w.checkLocks(s.Body.List, outer, scope)
+		if s.Else != nil {
+			w.checkNestedLocks(s.Else, outer, scope)
+		}
+	case *ast.SwitchStmt:
+		w.checkNestedLocks(s.Body, outer, scope)
+	case *ast.TypeSwitchStmt:
+		w.checkNestedLocks(s.Body, outer, scope)
+	case *ast.SelectStmt:
+		w.checkNestedLocks(s.Body, outer, scope)
+	case *ast.CaseClause:
+		w.checkLocks(s.Body, outer, scope)
+	case *ast.CommClause:
+		w.checkLocks(s.Body, outer, scope)
+	case *ast.ForStmt:
+		scope.loop = s.Body
+		w.checkLocks(s.Body.List, nil, scope)
+	case *ast.RangeStmt:
+		scope.loop = s.Body
+		w.checkLocks(s.Body.List, nil, scope)
+	}
+}
+
+// reachesEnd reports whether some path through the statements, followed by the outer ones, reaches their end,
+// that is, the end of the function or of the enclosing loop body.
+func (lintMutexUsage) reachesEnd(next []ast.Stmt, outer [][]ast.Stmt) bool {
+	flow := &releaseFlow{
+		releases: func(ast.Node) bool { return false },
+		nothingToRelease: func(ast.Expr) (whenTrue, whenFalse bool) {
+			return false, false
+		},
+	}
+
+	_, reachesEnd := flow.flow(next, false)
+	for _, stmts := range outer {
+		if !reachesEnd {
+			break
+		}
+		_, reachesEnd = flow.flow(stmts, false)
+	}
+	return reachesEnd
+}
+
+// heldOnEntry reports whether the lock is held when the body, of a function or a loop, is entered: its first use releases it.
+func (w lintMutexUsage) heldOnEntry(body *ast.BlockStmt, key string) bool {
+	held, found := false, false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.DeferStmt, *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if c, ok := w.lockCall(n); ok && c.key == key {
+				held, found = isUnlock(c.method), true
+			}
+		}
+		return !found
+	})
+	return held
+}
+
+// checkUnlocked creates a failure if the lock is not released on all the paths following its acquisition.
+// Returning the value holding the lock hands the lock over to the caller.
+func (w lintMutexUsage) checkUnlocked(lock lockCall, next []ast.Stmt, outer [][]ast.Stmt, scope lockScope) {
+	flow := &releaseFlow{
+		releases: func(node ast.Node) bool { return w.unlocks(node, lock) || w.returnsLock(node, lock, scope.results) },
+		nothingToRelease: func(ast.Expr) (whenTrue, whenFalse bool) {
+			return false, false
+		},
+	}
+
+	released, reachesEnd := flow.flow(next, false)
+	for _, stmts := range outer {
+		if released || !reachesEnd {
+			break
+		}
+		released, reachesEnd = flow.flow(stmts, false)
+	}
+
+	// the paths reaching the end of a loop body hold the lock in the next iteration,
+	// which must release it before acquiring it again
+	if flow.leaked || reachesEnd && !released && (scope.loop == nil || !w.heldOnEntry(scope.loop, lock.key)) {
+		unlock := lockCall{key: lock.key, method: unlockMethods[lock.method]}
+		w.addFailure(lock.call, 0.8, fmt.Sprintf("%s is not followed by %s on all the paths of the function, consider defer %s", lock, unlock, unlock))
+	}
+}
+
+// unlocks reports whether the node releases the lock, even in a deferred call or a closure.
+// An unlock method not matching the lock, such as Unlock for RLock, also releases it, and is reported.
+func (w lintMutexUsage) unlocks(node ast.Node, lock lockCall) bool {
+	if node == nil {
+		return false
+	}
+
+	unlocked := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if unlocked || !ok {
+			return !unlocked
+		}
+
+		c, ok := w.lockCall(call)
+		if !ok || c.key != lock.key || !isUnlock(c.method) {
+			return true
+		}
+		unlocked = true
+		if want := unlockMethods[lock.method]; c.method != want && !w.reported[call.Pos()] {
+			w.reported[call.Pos()] = true
+			sel := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+			expected := lockCall{key: lock.key, method: want}
+			w.onFailure(lint.Failure{
+				Category:        lint.FailureCategoryLogic,
+				Confidence:      1,
+				Node:            call,
+				Failure:         fmt.Sprintf("%s paired with %s, use %s", lock, c, expected),
+				ReplacementLine: replacementLine(w.file, sel.Sel, want),
+			})
+		}
+		return false
+	})
+	return unlocked
+}
+
+// deferredUnlock reports whether a call releasing the lock is deferred by the function before acquiring it.
+func (w lintMutexUsage) deferredUnlock(body *ast.BlockStmt, lock lockCall) bool {
+	deferred := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.DeferStmt:
+			if c, ok := w.lockCall(n.Call); ok && c.key == lock.key && isUnlock(c.method) && n.Pos() < lock.call.Pos() {
+				deferred = true
+			}
+		}
+		return !deferred
+	})
+	return deferred
+}
+
+// returnsLock reports whether the node is a return statement returning the variable holding the lock, or its address.
+func (w lintMutexUsage) returnsLock(node ast.Node, lock lockCall, results *ast.FieldList) bool {
+	ret, ok := node.(*ast.ReturnStmt)
+	if !ok {
+		return false
+	}
+
+	info := w.file.Pkg.TypesInfo()
+	root := lockRoot(ast.Unparen(lock.call.Fun).(*ast.SelectorExpr).X)
+	if root == nil || info.Uses[root] == nil {
+		return false
+	}
+	obj := info.Uses[root]
+
+	if len(ret.Results) == 0 && results != nil {
+		for _, field := range results.List {
+			for _, name := range field.Names {
+				if info.Defs[name] == obj {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, result := range ret.Results {
+		result = ast.Unparen(result)
+		if addr, ok := result.(*ast.UnaryExpr); ok && addr.Op == token.AND {
+			result = ast.Unparen(addr.X)
+		}
+		if id, ok := result.(*ast.Ident); ok && info.Uses[id] == obj {
+			return true
+		}
+	}
+	return false
+}
+
+// lockRoot returns the variable of the expression of a lock, such as s in s.shards[i].mu.
+func lockRoot(expr ast.Expr) *ast.Ident {
+	for {
+		switch x := ast.Unparen(expr).(type) {
+		case *ast.Ident:
+			return x
+		case *ast.SelectorExpr:
+			expr = x.X
+		case *ast.StarExpr:
+			expr = x.X
+		case *ast.IndexExpr:
+			expr = x.X
+		default:
+			return nil
+		}
+	}
+}
+
+// checkDeferredUnlock creates a failure if the unlock is deferred before acquiring the lock,
+// that is, if the lock is acquired by one of the following statements but not by the previous ones.
+func (w lintMutexUsage) checkDeferredUnlock(unlock lockCall, prev, next []ast.Stmt) {
+	for _, stmt := range prev {
+		locked := false
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				if c, ok := w.lockCall(call); ok && c.key == unlock.key && !isUnlock(c.method) {
+					locked = true
+				}
+			}
+			return !locked
+		})
+		if locked {
+			return
+		}
+	}
+
+	for _, stmt := range next {
+		c, deferred := w.stmtLockCall(stmt)
+		if deferred || c.call == nil || c.key != unlock.key || isUnlock(c.method) {
+			continue
+		}
+		w.addFailure(unlock.call, 1, fmt.Sprintf("defer %s is placed before %s, the lock could be released before being acquired; defer it after locking", unlock, c))
+		return
+	}
+}
+
+// checkParams creates failures for the parameters, or receiver, whose type contains a lock.
+func (w lintMutexUsage) checkParams(fields *ast.FieldList, kind, fix string) {
+	if fields == nil {
+		return
+	}
+
+	for _, field := range fields.List {
+		lock := containedLock(w.file.Pkg.TypeOf(field.Type))
+		if lock == "" {
+			continue
+		}
+		typ := w.file.Render(field.Type)
+		if len(field.Names) == 0 {
+			w.addFailure(field, 1, fmt.Sprintf("%s of type %s contains a %s and is passed by value, %s", kind, typ, lock, fix))
+			continue
+		}
+		for _, name := range field.Names {
+			w.addFailure(name, 1, fmt.Sprintf("%s %s of type %s contains a %s and is passed by value, %s", kind, name.Name, typ, lock, fix))
+		}
+	}
+}
+
+// checkRangeValue creates a failure if the value variable of the range statement copies values containing a lock.
+func (w lintMutexUsage) checkRangeValue(stmt *ast.RangeStmt) {
+	if stmt.Value == nil {
+		return
+	}
+	if id, ok := stmt.Value.(*ast.Ident); ok && id.Name == "_" {
+		return
+	}
+
+	typ := w.file.Pkg.TypeOf(stmt.Value)
+	if lock := containedLock(typ); lock != "" {
+		msg := fmt.Sprintf("range variable %s copies values of type %s containing a %s, range over the indexes or use pointers",
+			w.file.Render(stmt.Value), w.typeString(typ), lock)
+		w.addFailure(stmt.Value, 1, msg)
+	}
+}
+
+// checkCopiedElts creates failures for the elements of the composite literal dereferencing values containing a lock.
+func (w lintMutexUsage) checkCopiedElts(lit *ast.CompositeLit) {
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			elt = kv.Value
+		}
+		star, ok := ast.Unparen(elt).(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+
+		typ := w.file.Pkg.TypeOf(star)
+		if lock := containedLock(typ); lock != "" {
+			msg := fmt.Sprintf("composite literal copies %s of type %s containing a %s, use a pointer", w.file.Render(star), w.typeString(typ), lock)
+			w.addFailure(star, 1, msg)
+		}
+	}
+}
+
+// containedLock returns the lock type, such as sync.Mutex, of the given type or of one of its fields or elements,
+// not behind a pointer, or an empty string if it contains no lock.
+func containedLock(typ types.Type) string {
+	return findLock(typ, map[types.Type]bool{})
+}
+
+func findLock(typ types.Type, seen map[types.Type]bool) string {
+	if typ == nil || seen[typ] {
+		return ""
+	}
+	seen[typ] = true
+
+	typ = types.Unalias(typ)
+	if named, ok := typ.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "sync" && lockTypes[obj.Name()] {
+			return "sync." + obj.Name()
+		}
+	}
+
+	switch t := typ.Underlying().(type) {
+	case *types.Struct:
+		for field := range t.Fields() {
+			if lock := findLock(field.Type(), seen); lock != "" {
+				return lock
+			}
+		}
+	case *types.Array:
+		return findLock(t.Elem(), seen)
+	}
+	return ""
+}
+
+func (w lintMutexUsage) typeString(typ types.Type) string {
+	return types.TypeString(typ, types.RelativeTo(w.file.Pkg.TypesPkg()))
+}
+
+func (w lintMutexUsage) addFailure(node ast.Node, confidence float64, msg string) {
+	w.onFailure(lint.Failure{
+		Category:   lint.FailureCategoryLogic,
+		Confidence: confidence,
+		Node:       node,
+		Failure:    msg,
+	})
+}
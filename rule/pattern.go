@@ -0,0 +1,132 @@
+package rule
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"regexp"
+	"slices"
+
+	"github.com/mgechev/revive/internal/astpattern"
+	"github.com/mgechev/revive/lint"
+)
+
+// PatternRule is a rule reporting the code matching a syntax pattern defined in the configuration.
+type PatternRule struct {
+	name        string
+	source      string
+	pattern     *astpattern.Pattern
+	where       map[string]string
+	message     string
+	replacement string
+}
+
+var patternVariableRegexp = regexp.MustCompile(`\$\*?([A-Za-z_][A-Za-z0-9_]*)`)
+
+// NewPatternRule creates a rule with the given name, reporting the code matching the configured pattern.
+func NewPatternRule(name string, config lint.PatternRuleConfig) (*PatternRule, error) {
+	if config.Pattern == "" {
+		return nil, fmt.Errorf("the pattern rule %s requires a pattern", name)
+	}
+	pattern, err := astpattern.Compile(config.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("the pattern rule %s: %w", name, err)
+	}
+
+	for _, text := range []string{config.Message, config.Replacement} {
+		for _, m := range patternVariableRegexp.FindAllStringSubmatch(text, -1) {
+			if !slices.Contains(pattern.Variables, m[1]) {
+				return nil, fmt.Errorf("the pattern rule %s: unknown variable $%s, the pattern has %v", name, m[1], pattern.Variables)
+			}
+		}
+	}
+	for variable := range config.Where {
+		if !slices.Contains(pattern.Variables, variable) {
+			return nil, fmt.Errorf("the pattern rule %s: unknown variable %s in where, the pattern has %v", name, variable, pattern.Variables)
+		}
+	}
+
+	message := config.Message
+	if message == "" {
+		message = "code matches the pattern " + config.Pattern
+	}
+
+	return &PatternRule{
+		name:        name,
+		source:      config.Pattern,
+		pattern:     pattern,
+		where:       config.Where,
+		message:     message,
+		replacement: config.Replacement,
+	}, nil
+}
+
+// Apply applies the rule to given file.
+func (r *PatternRule) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
+	if len(r.where) > 0 {
+		// Type information is partial, rather than missing, when the package does not type check.
+		_ = file.Pkg.TypeCheck()
+	}
+
+	var failures []lint.Failure
+	ast.Inspect(file.AST, func(node ast.Node) bool {
+		captures, ok := r.pattern.Match(node)
+		if !ok || !r.satisfiesWhere(file, captures) {
+			return true
+		}
+
+		failure := lint.Failure{
+			Confidence: 1,
+			Node:       node,
+			Category:   lint.FailureCategoryStyle,
+			Failure:    r.expand(r.message, file, captures),
+		}
+		if r.replacement != "" {
+			failure.ReplacementLine = replacementLine(file, node, r.expand(r.replacement, file, captures))
+		}
+		failures = append(failures, failure)
+		return true
+	})
+
+	return failures
+}
+
+// Name returns the rule name.
+func (r *PatternRule) Name() string {
+	return r.name
+}
+
+// satisfiesWhere reports whether the types of the captured nodes are those required by the where constraints.
+// A node without type information does not satisfy a constraint.
+func (r *PatternRule) satisfiesWhere(file *lint.File, captures astpattern.Captures) bool {
+	for variable, want := range r.where {
+		for _, node := range captures[variable] {
+			expr, ok := node.(ast.Expr)
+			if !ok {
+				return false
+			}
+			typ := file.Pkg.TypeOf(expr)
+			if typ == nil || types.TypeString(typ, nil) != want {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// expand replaces the variables in text with the source code of the nodes they captured.
+func (*PatternRule) expand(text string, file *lint.File, captures astpattern.Captures) string {
+	return patternVariableRegexp.ReplaceAllStringFunc(text, func(v string) string {
+		nodes := captures[patternVariableRegexp.FindStringSubmatch(v)[1]]
+		if len(nodes) == 0 {
+			return ""
+		}
+		return string(nodeSource(file, nodes[0], nodes[len(nodes)-1]))
+	})
+}
+
+// nodeSource returns the source code from the start of the first node to the end of the last one.
+func nodeSource(file *lint.File, first, last ast.Node) []byte {
+	start, end := file.ToPosition(first.Pos()), file.ToPosition(last.End())
+	return file.Content()[start.Offset:end.Offset]
+}
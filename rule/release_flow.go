@@ -0,0 +1,213 @@
+package rule
+
+import (
+	"go/ast"
+	"go/token"
+
+	"github.com/mgechev/revive/internal/ifelse"
+)
+
+// releaseFlow follows the paths of a function through its statements, to check a resource acquired
+// before them, such as a file or a lock, is released on all the paths.
+type releaseFlow struct {
+	// releases reports whether the node releases the resource.
+	releases func(node ast.Node) bool
+	// nothingToRelease reports whether there is nothing to release in the branches of an if statement,
+	// when its condition is true and when it is false, such as when the resource is nil.
+	nothingToRelease func(cond ast.Expr) (whenTrue, whenFalse bool)
+	// leaked is set if a path returns, or leaves the followed statements, without releasing the resource.
+	leaked bool
+	// breakTargets are the enclosing statements a break can leave, innermost last.
+	breakTargets []*breakTarget
+	// label is the label of the labeled statement being followed, if any.
+	label string
+}
+
+// breakTarget is a for, range, switch or select statement a break statement can leave.
+type breakTarget struct {
+	label string
+	// loop is set for the for and range statements, that continue statements can target too.
+	loop bool
+	// broken is set if a path leaves the statement with a break, without releasing the resource.
+	broken bool
+}
+
+// enter returns the target of the break statements in the statement being entered.
+func (f *releaseFlow) enter(loop bool) *breakTarget {
+	target := &breakTarget{label: f.label, loop: loop}
+	f.label = ""
+	f.breakTargets = append(f.breakTargets, target)
+	return target
+}
+
+// leave leaves the statement entered last, and reports whether some break statement left it.
+func (f *releaseFlow) leave() (broken bool) {
+	target := f.breakTargets[len(f.breakTargets)-1]
+	f.breakTargets = f.breakTargets[:len(f.breakTargets)-1]
+	return target.broken
+}
+
+// branchTarget returns the statement targeted by the break or continue statement, or nil if it is not followed.
+func (f *releaseFlow) branchTarget(stmt *ast.BranchStmt) *breakTarget {
+	for i := len(f.breakTargets) - 1; i >= 0; i-- {
+		target := f.breakTargets[i]
+		if stmt.Tok == token.CONTINUE && !target.loop {
+			continue
+		}
+		if stmt.Label == nil || target.label == stmt.Label.Name {
+			return target
+		}
+	}
+	return nil
+}
+
+// flow follows the paths through the statements, starting with the resource released or not.
+// It reports whether the resource is released on all the paths reaching the end of the statements,
+// and whether some path reaches it. A path returning, or leaving the statements with a break, continue
+// or goto statement, without releasing the resource leaks it.
+func (f *releaseFlow) flow(stmts []ast.Stmt, released bool) (releasedAtEnd, reachesEnd bool) {
+	for _, stmt := range stmts {
+		if released {
+			return true, true
+		}
+
+		var fallsThrough bool
+		released, fallsThrough = f.flowStmt(stmt)
+		if !fallsThrough {
+			return released, false
+		}
+	}
+	return released, true
+}
+
+// flowStmt follows the paths through a statement, starting with the resource not released.
+func (f *releaseFlow) flowStmt(stmt ast.Stmt) (releasedAtEnd, reachesEnd bool) {
+	switch s := stmt.(type) {
+	case *ast.BlockStmt:
+		return f.flow(s.List, false)
+	case *ast.LabeledStmt:
+		f.label = s.Label.Name
+		defer func() { f.label = "" }()
+		return f.flowStmt(s.Stmt)
+	case *ast.IfStmt:
+		return f.flowIf(s)
+	case *ast.ForStmt:
+		target := f.enter(true)
+		released := f.releases(s.Init) || f.releases(s.Cond)
+		if !released {
+			// the loop body might not run: it only matters for the paths returning from the loop
+			f.flow(s.Body.List, false)
+		}
+		f.leave()
+		if s.Cond == nil && !target.broken {
+			return true, false // the loop never ends
+		}
+		return released, true
+	case *ast.RangeStmt:
+		f.enter(true)
+		released := f.releases(s.X)
+		if !released {
+			f.flow(s.Body.List, false)
+		}
+		f.leave()
+		return released, true
+	case *ast.SwitchStmt:
+		if f.releases(s.Init) || f.releases(s.Tag) {
+			return true, true
+		}
+		return f.flowBreakableClauses(s.Body, true)
+	case *ast.TypeSwitchStmt:
+		if f.releases(s.Init) || f.releases(s.Assign) {
+			return true, true
+		}
+		return f.flowBreakableClauses(s.Body, true)
+	case *ast.SelectStmt:
+		return f.flowBreakableClauses(s.Body, false)
+	case *ast.BranchStmt:
+		target := f.branchTarget(s)
+		switch {
+		case target == nil || s.Tok == token.GOTO:
+			// the path leaves the followed statements
+		case s.Tok == token.BREAK:
+			// the path continues after the statement left
+			target.broken = true
+			return false, false
+		case s.Tok == token.CONTINUE:
+			// the path continues with the next iteration of a loop followed for its returning paths only
+			return true, false
+		}
+	}
+
+	switch ifelse.StmtBranch(stmt).BranchKind {
+	case ifelse.Return, ifelse.Break, ifelse.Continue, ifelse.Goto:
+		if !f.releases(stmt) {
+			f.leaked = true
+		}
+		return true, false
+	case ifelse.Panic, ifelse.Exit:
+		return true, false // the function does not return
+	}
+	return f.releases(stmt), true
+}
+
+// flowBreakableClauses follows the paths through the clauses of a switch or select statement,
+// and the paths leaving it with a break statement.
+func (f *releaseFlow) flowBreakableClauses(body *ast.BlockStmt, canSkip bool) (releasedAtEnd, reachesEnd bool) {
+	f.enter(false)
+	released, fallsThrough := f.flowClauses(body, canSkip)
+	if f.leave() {
+		return false, true
+	}
+	return released, fallsThrough
+}
+
+// flowIf follows the paths through the branches of an if statement.
+func (f *releaseFlow) flowIf(s *ast.IfStmt) (releasedAtEnd, reachesEnd bool) {
+	if f.releases(s.Init) || f.releases(s.Cond) {
+		return true, true
+	}
+
+	nothingInThen, nothingInElse := f.nothingToRelease(s.Cond)
+
+	thenReleased, thenFallsThrough := f.flow(s.Body.List, nothingInThen)
+	elseReleased, elseFallsThrough := nothingInElse, true
+	if s.Else != nil {
+		elseReleased, elseFallsThrough = f.flow([]ast.Stmt{s.Else}, nothingInElse)
+	}
+
+	released := (!thenFallsThrough || thenReleased) && (!elseFallsThrough || elseReleased)
+	return released, thenFallsThrough || elseFallsThrough
+}
+
+// flowClauses follows the paths through the clauses of a switch or select statement.
+// Unless the statement has a default clause, the path not entering any clause is followed too.
+func (f *releaseFlow) flowClauses(body *ast.BlockStmt, canSkip bool) (releasedAtEnd, reachesEnd bool) {
+	released, fallsThrough := true, false
+	for _, stmt := range body.List {
+		var clauseBody []ast.Stmt
+		clauseReleased := false
+		switch clause := stmt.(type) {
+		case *ast.CaseClause:
+			canSkip = canSkip && clause.List != nil
+			clauseBody = clause.Body
+			for _, expr := range clause.List {
+				clauseReleased = clauseReleased || f.releases(expr)
+			}
+		case *ast.CommClause:
+			canSkip = canSkip && clause.Comm != nil
+			clauseBody = clause.Body
+			clauseReleased = f.releases(clause.Comm)
+		}
+
+		clauseReleased, clauseFallsThrough := f.flow(clauseBody, clauseReleased)
+		if clauseFallsThrough {
+			released = released && clauseReleased
+			fallsThrough = true
+		}
+	}
+
+	if canSkip {
+		return false, true
+	}
+	return released, fallsThrough
+}
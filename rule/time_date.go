@@ -11,7 +11,6 @@ import (
 
 	"github.com/mgechev/revive/internal/astutils"
 	"github.com/mgechev/revive/lint"
-	"github.com/mgechev/revive/logging"
 )
 
 // TimeDateRule lints the way [time.Date] is used.
@@ -234,12 +233,7 @@ func (w *lintTimeDate) Visit(n ast.Node) ast.Visitor {
 			// This is not supposed to happen, let's be defensive
 			// log the error, but continue
 
-			logger, errLogger := logging.GetLogger()
-			if errLogger != nil {
-				// This is not supposed to happen, discard both errors
-				continue
-			}
-			logger.With(
+			w.file.Logger().With(
 				"value", bl.Value,
 				"kind", bl.Kind,
 				"error", err.Error(),
@@ -13,20 +13,23 @@ import (
 type TimeEqualRule struct{}
 
 // Apply applies the rule to given file.
-func (*TimeEqualRule) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
-	var failures []lint.Failure
+func (r *TimeEqualRule) Apply(file *lint.File, arguments lint.Arguments) []lint.Failure {
+	return lint.ApplyNodeRule(r, file, arguments)
+}
 
-	onFailure := func(failure lint.Failure) {
-		failures = append(failures, failure)
-	}
+// NodeTypes returns the types of the nodes checked by the rule.
+func (*TimeEqualRule) NodeTypes() []ast.Node {
+	return []ast.Node{(*ast.BinaryExpr)(nil)}
+}
 
-	w := &lintTimeEqual{file, onFailure}
-	if w.file.Pkg.TypeCheck() != nil {
+// CheckNodes returns the function checking the nodes of the file.
+func (*TimeEqualRule) CheckNodes(file *lint.File, _ lint.Arguments, onFailure func(lint.Failure)) func(ast.Node) {
+	if file.Pkg.TypeCheck() != nil {
 		return nil
 	}
 
-	ast.Walk(w, file.AST)
-	return failures
+	w := &lintTimeEqual{file, onFailure}
+	return w.check
 }
 
 // Name returns the rule name.
@@ -39,23 +42,19 @@ type lintTimeEqual struct {
 	onFailure func(lint.Failure)
 }
 
-func (l *lintTimeEqual) Visit(node ast.Node) ast.Visitor {
-	expr, ok := node.(*ast.BinaryExpr)
-	if !ok {
-		return l
-	}
-
+func (l *lintTimeEqual) check(node ast.Node) {
+	expr := node.(*ast.BinaryExpr)
 	switch expr.Op {
 	case token.EQL, token.NEQ:
 	default:
-		return l
+		return
 	}
 
 	typeOfX := l.file.Pkg.TypeOf(expr.X)
 	typeOfY := l.file.Pkg.TypeOf(expr.Y)
 	bothAreOfTimeType := isNamedType(typeOfX, "time", "Time") && isNamedType(typeOfY, "time", "Time")
 	if !bothAreOfTimeType {
-		return l
+		return
 	}
 
 	negateStr := ""
@@ -69,6 +68,4 @@ func (l *lintTimeEqual) Visit(node ast.Node) ast.Visitor {
 		Node:       node,
 		Failure:    fmt.Sprintf("use %s%s.Equal(%s) instead of %q operator", negateStr, astutils.GoFmt(expr.X), astutils.GoFmt(expr.Y), expr.Op),
 	})
-
-	return l
 }
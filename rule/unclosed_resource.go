@@ -0,0 +1,360 @@
+package rule
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/mgechev/revive/lint"
+)
+
+// UnclosedResourceRule spots resources, such as files, rows and response bodies, returned by function calls
+// and not released on all paths of the function: neither closed, nor returned or passed elsewhere.
+type UnclosedResourceRule struct {
+	resources []resourceKind
+}
+
+// resourceKind is a type of resources released by calling a method, possibly of one of its fields,
+// as in (*net/http.Response).Body.Close.
+type resourceKind struct {
+	typ  string   // the type of the resource, as given by [types.TypeString]
+	path []string // the fields leading to the method releasing the resource, and the method
+}
+
+// defaultResources are the resources released by other means than an io.Closer implementation.
+var defaultResources = []string{
+	"(*net/http.Response).Body.Close",
+	"(*time.Ticker).Stop",
+}
+
+// parseResourceKind parses a resource kind in the form (T).Method or (T).Field.Method.
+func parseResourceKind(s string) (resourceKind, error) {
+	typ, path, ok := strings.Cut(strings.TrimSpace(s), ").")
+	if !ok || !strings.HasPrefix(typ, "(") || len(typ) < 2 || path == "" {
+		return resourceKind{}, fmt.Errorf("invalid resource %q, expected a type and a method such as (*database/sql.Rows).Close", s)
+	}
+	return resourceKind{typ: typ[1:], path: strings.Split(path, ".")}, nil
+}
+
+// Configure validates the rule configuration, and configures the rule accordingly.
+//
+// Configuration implements the [lint.ConfigurableRule] interface.
+func (r *UnclosedResourceRule) Configure(arguments lint.Arguments) error {
+	r.resources = nil
+	for _, resource := range defaultResources {
+		kind, err := parseResourceKind(resource)
+		if err != nil {
+			return err
+		}
+		r.resources = append(r.resources, kind)
+	}
+
+	for _, arg := range arguments {
+		resource, ok := arg.(string)
+		if !ok {
+			return fmt.Errorf("invalid argument to the unclosed-resource rule. Expecting a string, got %T", arg)
+		}
+		kind, err := parseResourceKind(resource)
+		if err != nil {
+			return fmt.Errorf("invalid argument to the unclosed-resource rule: %w", err)
+		}
+		r.resources = append(r.resources, kind)
+	}
+	return nil
+}
+
+// Apply applies the rule to given file.
+func (r *UnclosedResourceRule) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
+	var failures []lint.Failure
+
+	file.Pkg.TypeCheck()
+	w := lintUnclosedResources{
+		file:      file,
+		info:      file.Pkg.TypesInfo(),
+		resources: r.resources,
+		onFailure: func(failure lint.Failure) {
+			failures = append(failures, failure)
+		},
+	}
+
+	ast.Inspect(file.AST, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.BlockStmt:
+			w.checkStmts(n.List)
+		case *ast.CaseClause:
+			w.checkStmts(n.Body)
+		case *ast.CommClause:
+			w.checkStmts(n.Body)
+		}
+		return true
+	})
+
+	return failures
+}
+
+// Name returns the rule name.
+func (*UnclosedResourceRule) Name() string {
+	return "unclosed-resource"
+}
+
+type lintUnclosedResources struct {
+	file      *lint.File
+	info      *types.Info
+	resources []resourceKind
+	onFailure func(lint.Failure)
+}
+
+// resource is a variable holding a resource returned by a call.
+type resource struct {
+	obj  types.Object
+	path []string // the fields leading to the method releasing the resource, and the method
+	// err is the error returned with the resource, if any.
+	err types.Object
+}
+
+// releasePath returns the fields and the method releasing the resources of the given type, or nil.
+func (w lintUnclosedResources) releasePath(typ types.Type) []string {
+	name := types.TypeString(typ, nil)
+	for _, kind := range w.resources {
+		if kind.typ == name {
+			return kind.path
+		}
+	}
+	if hasMethod(typ, "Close", errorType) { // io.Closer
+		return []string{"Close"}
+	}
+	return nil
+}
+
+// checkStmts checks the resources declared by the statements of a list are released
+// by the statements following their declaration.
+func (w lintUnclosedResources) checkStmts(stmts []ast.Stmt) {
+	for i, stmt := range stmts {
+		var lhs []*ast.Ident
+		var value ast.Expr
+		switch s := stmt.(type) {
+		case *ast.AssignStmt:
+			if s.Tok != token.DEFINE || len(s.Rhs) != 1 {
+				continue
+			}
+			for _, expr := range s.Lhs {
+				id, _ := expr.(*ast.Ident)
+				lhs = append(lhs, id)
+			}
+			value = s.Rhs[0]
+		case *ast.DeclStmt:
+			decl, ok := s.Decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.VAR || len(decl.Specs) != 1 {
+				continue
+			}
+			spec := decl.Specs[0].(*ast.ValueSpec)
+			if len(spec.Values) != 1 {
+				continue
+			}
+			lhs, value = spec.Names, spec.Values[0]
+		default:
+			continue
+		}
+
+		call, ok := ast.Unparen(value).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		for _, res := range w.callResources(call, lhs) {
+			if res.obj == nil {
+				w.addFailure(call, res) // discarded
+				continue
+			}
+
+			flow := &releaseFlow{
+				releases: func(node ast.Node) bool { return w.releases(node, res) },
+				nothingToRelease: func(cond ast.Expr) (bool, bool) {
+					return w.nothingToRelease(cond, res)
+				},
+			}
+			if released, reachesEnd := flow.flow(stmts[i+1:], false); reachesEnd && !released || flow.leaked {
+				w.addFailure(call, res)
+			}
+		}
+	}
+}
+
+// callResources returns the resources returned by the call and assigned to the given variables.
+// A resource assigned to the blank identifier has no object.
+func (w lintUnclosedResources) callResources(call *ast.CallExpr, lhs []*ast.Ident) []*resource {
+	var results []types.Type
+	switch t := w.file.Pkg.TypeOf(call).(type) {
+	case nil:
+		return nil
+	case *types.Tuple:
+		for v := range t.Variables() {
+			results = append(results, v.Type())
+		}
+	default:
+		results = []types.Type{t}
+	}
+	if len(results) != len(lhs) {
+		return nil
+	}
+
+	var resources []*resource
+	var errObj types.Object
+	for i, typ := range results {
+		if lhs[i] == nil {
+			continue
+		}
+		if types.Identical(typ, errorType) {
+			errObj = w.info.ObjectOf(lhs[i])
+			continue
+		}
+
+		path := w.releasePath(typ)
+		if path == nil {
+			continue
+		}
+		res := &resource{path: path}
+		if lhs[i].Name != "_" {
+			res.obj = w.info.Defs[lhs[i]]
+			if res.obj == nil {
+				continue // not a new variable
+			}
+		}
+		resources = append(resources, res)
+	}
+
+	for _, res := range resources {
+		res.err = errObj
+	}
+	return resources
+}
+
+func (w lintUnclosedResources) addFailure(call *ast.CallExpr, res *resource) {
+	callee := w.file.Render(call.Fun)
+	msg := fmt.Sprintf("the resource returned by %s is discarded without being released", callee)
+	if res.obj != nil {
+		release := res.obj.Name() + "." + strings.Join(res.path, ".") + "()"
+		msg = fmt.Sprintf("%s returned by %s is not released on all paths, call %s, return it or pass it elsewhere", res.obj.Name(), callee, release)
+	}
+
+	w.onFailure(lint.Failure{
+		Category:   lint.FailureCategoryBadPractice,
+		Confidence: 0.8,
+		Node:       call,
+		Failure:    msg,
+	})
+}
+
+// nothingToRelease reports whether the resource is nil, or the call returning it failed,
+// when the condition is true and when it is false, as with err != nil or res == nil:
+// the branch taken then has nothing to release.
+func (w lintUnclosedResources) nothingToRelease(cond ast.Expr, res *resource) (whenTrue, whenFalse bool) {
+	expr, ok := ast.Unparen(cond).(*ast.BinaryExpr)
+	if !ok || expr.Op != token.EQL && expr.Op != token.NEQ {
+		return false, false
+	}
+
+	x, isIdent := ast.Unparen(expr.X).(*ast.Ident)
+	if !isIdent || !w.isNil(expr.Y) {
+		return false, false
+	}
+	switch w.info.Uses[x] {
+	case nil:
+		return false, false
+	case res.err:
+		// the resource is not valid if the call failed
+		return expr.Op == token.NEQ, expr.Op == token.EQL
+	case res.obj:
+		return expr.Op == token.EQL, expr.Op == token.NEQ
+	}
+	return false, false
+}
+
+func (w lintUnclosedResources) isNil(expr ast.Expr) bool {
+	tv, ok := w.info.Types[expr]
+	return ok && tv.IsNil()
+}
+
+// releases reports whether the node releases the resource: calls its release method, even deferred,
+// or passes it elsewhere, e.g. as an argument, a result, in a closure or an assignment.
+// Calling other methods of the resource, reading its fields or comparing it does not release it.
+func (w lintUnclosedResources) releases(node ast.Node, res *resource) bool {
+	if node == nil {
+		return false
+	}
+
+	released := false
+	var stack []ast.Node
+	ast.Inspect(node, func(n ast.Node) bool {
+		if released {
+			return false
+		}
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			if w.uses(n, res) {
+				released = true // captured by a closure
+				return false
+			}
+		case *ast.Ident:
+			if w.info.Uses[n] == res.obj {
+				released = w.isReleasingUse(n, stack, res)
+			}
+		}
+		stack = append(stack, n)
+		return true
+	})
+	return released
+}
+
+// uses reports whether the node uses the resource.
+func (w lintUnclosedResources) uses(node ast.Node, res *resource) bool {
+	used := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && w.info.Uses[id] == res.obj {
+			used = true
+		}
+		return !used
+	})
+	return used
+}
+
+// isReleasingUse reports whether the use of the resource, given the stack of its ancestors, releases it.
+func (lintUnclosedResources) isReleasingUse(id *ast.Ident, stack []ast.Node, res *resource) bool {
+	if len(stack) == 0 {
+		return true
+	}
+
+	switch parent := stack[len(stack)-1].(type) {
+	case *ast.SelectorExpr:
+		// the release method, possibly of a field, called or passed as a method value
+		var x ast.Expr = id
+		for i, name := range res.path {
+			if len(stack) <= i {
+				return false
+			}
+			sel, ok := stack[len(stack)-1-i].(*ast.SelectorExpr)
+			if !ok || sel.X != x || sel.Sel.Name != name {
+				return false
+			}
+			x = sel
+		}
+		return true
+	case *ast.BinaryExpr:
+		return false // comparison
+	case *ast.StarExpr, *ast.IndexExpr, *ast.IndexListExpr, *ast.SliceExpr, *ast.IncDecStmt:
+		return false
+	case *ast.AssignStmt:
+		for _, lhs := range parent.Lhs {
+			if lhs == id {
+				return false // assigned another value
+			}
+		}
+	}
+	return true
+}
@@ -6,25 +6,94 @@ import (
 	"go/ast"
 	"go/types"
 	"regexp"
+	"slices"
 	"strings"
 
+	"golang.org/x/tools/go/types/typeutil"
+
 	"github.com/mgechev/revive/internal/astutils"
 	"github.com/mgechev/revive/lint"
 )
 
 // UnhandledErrorRule warns on unhandled errors returned by function calls.
 type UnhandledErrorRule struct {
-	ignoreList []*regexp.Regexp
+	ignoreList            []*regexp.Regexp
+	ignoredFuncs          []ignoredFunc // the configured functions, in addition to defaultIgnoredFuncs
+	checkBlankAssignments bool
+	checkDefer            bool
+	checkGo               bool
+}
+
+// ignoredFunc identifies functions whose errors are ignored by their fully-qualified name,
+// as given by [types.Func.FullName], such as fmt.Fprintf or (*bytes.Buffer).Write.
+type ignoredFunc struct {
+	qualifier string         // the package path, or the parenthesized receiver type of a method
+	name      *regexp.Regexp // the function name, where * matches any characters
+	argType   string         // if not empty, the type the first argument of the call must have
+}
+
+// defaultIgnoredFuncs are the functions whose errors are always ignored, configured or not:
+// writing to memory buffers never fails.
+var defaultIgnoredFuncs = []ignoredFunc{
+	mustParseIgnoredFunc("fmt.Fprintf(*bytes.Buffer)"),
+	mustParseIgnoredFunc("fmt.Fprintf(*strings.Builder)"),
+}
+
+func mustParseIgnoredFunc(s string) ignoredFunc {
+	result, err := parseIgnoredFunc(s)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// parseIgnoredFunc parses a fully-qualified function name, optionally followed by the type of the
+// first argument of the call in parentheses, e.g. fmt.Fprint*(*strings.Builder).
+func parseIgnoredFunc(s string) (ignoredFunc, error) {
+	var result ignoredFunc
+	name := strings.TrimSpace(s)
+	if open := strings.LastIndex(name, "("); open > 0 && strings.HasSuffix(name, ")") {
+		name, result.argType = name[:open], name[open+1:len(name)-1]
+	}
+
+	qualifier, funcName := splitFuncName(name)
+	if qualifier == "" || funcName == "" || strings.ContainsAny(funcName, "()") ||
+		strings.HasPrefix(qualifier, "(") != strings.HasSuffix(qualifier, ")") {
+		return result, fmt.Errorf("invalid function %q, expected a fully-qualified name such as fmt.Fprintf or (*bytes.Buffer).Write", s)
+	}
+
+	result.qualifier = qualifier
+	result.name = regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(funcName), `\*`, ".*") + "$")
+	return result, nil
+}
+
+// splitFuncName splits a fully-qualified function name into its qualifier and its name.
+func splitFuncName(fullName string) (qualifier, name string) {
+	dot := strings.LastIndex(fullName, ".")
+	if dot < 0 {
+		return "", fullName
+	}
+	return fullName[:dot], fullName[dot+1:]
 }
 
 // Configure validates the rule configuration, and configures the rule accordingly.
 //
 // Configuration implements the [lint.ConfigurableRule] interface.
 func (r *UnhandledErrorRule) Configure(arguments lint.Arguments) error {
+	// reconfiguring the rule does not keep the previous options
+	*r = UnhandledErrorRule{}
+
 	for _, arg := range arguments {
+		if options, ok := arg.(map[string]any); ok {
+			if err := r.configureOptions(options); err != nil {
+				return err
+			}
+			continue
+		}
+
 		argStr, ok := arg.(string)
 		if !ok {
-			return fmt.Errorf("invalid argument to the unhandled-error rule. Expecting a string, got %T", arg)
+			return fmt.Errorf("invalid argument to the unhandled-error rule. Expecting a string or a k,v map, got %T", arg)
 		}
 
 		argStr = strings.Trim(argStr, " ")
@@ -42,13 +111,56 @@ func (r *UnhandledErrorRule) Configure(arguments lint.Arguments) error {
 	return nil
 }
 
+func (r *UnhandledErrorRule) configureOptions(options map[string]any) error {
+	for k, v := range options {
+		switch {
+		case isRuleOption(k, "checkBlankAssignments"), isRuleOption(k, "checkDefer"), isRuleOption(k, "checkGo"):
+			enabled, ok := v.(bool)
+			if !ok {
+				return fmt.Errorf("invalid argument to the %s option of the unhandled-error rule, bool expected. Got '%v' (%T)", k, v, v)
+			}
+			switch {
+			case isRuleOption(k, "checkBlankAssignments"):
+				r.checkBlankAssignments = enabled
+			case isRuleOption(k, "checkDefer"):
+				r.checkDefer = enabled
+			default:
+				r.checkGo = enabled
+			}
+		case isRuleOption(k, "ignoreFuncs"):
+			funcs, ok := v.([]any)
+			if !ok {
+				return fmt.Errorf("invalid argument to the %s option of the unhandled-error rule, []string expected. Got '%v' (%T)", k, v, v)
+			}
+			for _, f := range funcs {
+				name, ok := f.(string)
+				if !ok {
+					return fmt.Errorf("invalid argument to the %s option of the unhandled-error rule, string expected. Got '%v' (%T)", k, f, f)
+				}
+				ignored, err := parseIgnoredFunc(name)
+				if err != nil {
+					return fmt.Errorf("invalid argument to the %s option of the unhandled-error rule: %w", k, err)
+				}
+				r.ignoredFuncs = append(r.ignoredFuncs, ignored)
+			}
+		default:
+			return fmt.Errorf("unknown option %q for the unhandled-error rule", k)
+		}
+	}
+	return nil
+}
+
 // Apply applies the rule to given file.
 func (r *UnhandledErrorRule) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
 	var failures []lint.Failure
 
 	walker := &lintUnhandledErrors{
-		ignoreList: r.ignoreList,
-		pkg:        file.Pkg,
+		ignoreList:            r.ignoreList,
+		ignoredFuncs:          slices.Concat(defaultIgnoredFuncs, r.ignoredFuncs),
+		checkBlankAssignments: r.checkBlankAssignments,
+		checkDefer:            r.checkDefer,
+		checkGo:               r.checkGo,
+		pkg:                   file.Pkg,
 		onFailure: func(failure lint.Failure) {
 			failures = append(failures, failure)
 		},
@@ -66,53 +178,111 @@ func (*UnhandledErrorRule) Name() string {
 }
 
 type lintUnhandledErrors struct {
-	ignoreList []*regexp.Regexp
-	pkg        *lint.Package
-	onFailure  func(lint.Failure)
+	ignoreList            []*regexp.Regexp
+	ignoredFuncs          []ignoredFunc
+	checkBlankAssignments bool
+	checkDefer            bool
+	checkGo               bool
+	pkg                   *lint.Package
+	onFailure             func(lint.Failure)
 }
 
-// Visit looks for statements that are function calls.
-// If the called function returns a value of type error a failure will be created.
+// Visit looks for function calls whose results are discarded: statements that are function calls,
+// and, if configured, deferred calls, calls started in goroutines, and assignments to the blank identifier.
+// If a discarded result of the called function is of type error a failure will be created.
 func (w *lintUnhandledErrors) Visit(node ast.Node) ast.Visitor {
-	if n, ok := node.(*ast.ExprStmt); ok {
+	switch n := node.(type) {
+	case *ast.ExprStmt:
 		fCall, ok := n.X.(*ast.CallExpr)
 		if !ok {
 			return nil // not a function call
 		}
 
-		funcType := w.pkg.TypeOf(fCall)
-		if funcType == nil {
-			return nil // skip, type info not available
+		w.checkDiscardedCall(fCall, "Unhandled error in call to function %v")
+	case *ast.DeferStmt:
+		if w.checkDefer {
+			w.checkDiscardedCall(n.Call, "Unhandled error in deferred call to function %v")
 		}
-
-		switch t := funcType.(type) {
-		case *types.Named:
-			if !w.isTypeError(t) {
-				return nil // func call does not return an error
+	case *ast.GoStmt:
+		if w.checkGo {
+			w.checkDiscardedCall(n.Call, "Unhandled error in call to function %v started in a goroutine")
+		}
+	case *ast.AssignStmt:
+		if w.checkBlankAssignments {
+			w.checkBlankAssignment(n.Lhs, n.Rhs)
+		}
+	case *ast.ValueSpec:
+		if w.checkBlankAssignments {
+			lhs := make([]ast.Expr, len(n.Names))
+			for i, name := range n.Names {
+				lhs[i] = name
 			}
+			w.checkBlankAssignment(lhs, n.Values)
+		}
+	}
+	return w
+}
 
-			w.addFailure(fCall)
-		default:
-			retTypes, ok := funcType.Underlying().(*types.Tuple)
-			if !ok {
-				return nil // skip, unable to retrieve return type of the called function
-			}
+// checkDiscardedCall creates a failure if the call, whose results are all discarded, returns an error.
+func (w *lintUnhandledErrors) checkDiscardedCall(call *ast.CallExpr, format string) {
+	if slices.Contains(w.errorResults(call), true) {
+		w.addFailure(call, format)
+	}
+}
 
-			if w.returnsAnError(retTypes) {
-				w.addFailure(fCall)
-			}
+// checkBlankAssignment creates a failure for each call whose error result is assigned to the blank identifier.
+func (w *lintUnhandledErrors) checkBlankAssignment(lhs, rhs []ast.Expr) {
+	if len(rhs) == 1 {
+		w.checkBlankAssignedCall(lhs, rhs[0])
+		return
+	}
+
+	for i, value := range rhs {
+		if i < len(lhs) {
+			w.checkBlankAssignedCall(lhs[i:i+1], value)
 		}
 	}
-	return w
 }
 
-func (w *lintUnhandledErrors) addFailure(n *ast.CallExpr) {
-	name := w.funcName(n)
-	if w.isIgnoredFunc(name) {
+func (w *lintUnhandledErrors) checkBlankAssignedCall(lhs []ast.Expr, value ast.Expr) {
+	call, ok := ast.Unparen(value).(*ast.CallExpr)
+	if !ok {
 		return
 	}
 
-	if w.isSafeFprintfToBuffer(n) {
+	results := w.errorResults(call)
+	if len(results) != len(lhs) {
+		return
+	}
+
+	for i, isError := range results {
+		if isError && astutils.IsIdent(lhs[i], "_") {
+			w.addFailure(call, "Unhandled error in call to function %v, assigned to the blank identifier")
+			return
+		}
+	}
+}
+
+// errorResults returns, for each result of the call, whether it is of type error.
+// It returns nil if type information is not available.
+func (w *lintUnhandledErrors) errorResults(call *ast.CallExpr) []bool {
+	switch t := w.pkg.TypeOf(call).(type) {
+	case nil:
+		return nil // skip, type info not available
+	case *types.Tuple:
+		results := make([]bool, t.Len())
+		for i := range t.Len() {
+			results[i] = w.isTypeError(t.At(i).Type())
+		}
+		return results
+	default:
+		return []bool{w.isTypeError(t)}
+	}
+}
+
+func (w *lintUnhandledErrors) addFailure(n *ast.CallExpr, format string) {
+	name := w.funcName(n)
+	if w.isIgnoredFunc(name) || w.isIgnoredCall(n) {
 		return
 	}
 
@@ -120,7 +290,7 @@ func (w *lintUnhandledErrors) addFailure(n *ast.CallExpr) {
 		Category:   lint.FailureCategoryBadPractice,
 		Confidence: 1,
 		Node:       n,
-		Failure:    fmt.Sprintf("Unhandled error in call to function %v", name),
+		Failure:    fmt.Sprintf(format, name),
 	})
 }
 
@@ -148,22 +318,46 @@ func (w *lintUnhandledErrors) isIgnoredFunc(funcName string) bool {
 	return false
 }
 
-func (*lintUnhandledErrors) isTypeError(t *types.Named) bool {
-	const errorTypeName = "_.error"
-
-	return t.Obj().Id() == errorTypeName
-}
+// isIgnoredCall reports whether the function called, resolved from type information, is in the ignored functions.
+func (w *lintUnhandledErrors) isIgnoredCall(call *ast.CallExpr) bool {
+	fn, ok := typeutil.Callee(w.pkg.TypesInfo(), call).(*types.Func)
+	if !ok {
+		return false
+	}
 
-func (w *lintUnhandledErrors) returnsAnError(tt *types.Tuple) bool {
-	for v := range tt.Variables() {
-		nt, ok := v.Type().(*types.Named)
-		if ok && w.isTypeError(nt) {
+	qualifier, name := splitFuncName(fn.Origin().FullName())
+	for _, ignored := range w.ignoredFuncs {
+		if ignored.qualifier != qualifier || !ignored.name.MatchString(name) {
+			continue
+		}
+		if ignored.argType == "" || w.firstArgType(call) == ignored.argType {
 			return true
 		}
 	}
+
 	return false
 }
 
+func (w *lintUnhandledErrors) firstArgType(call *ast.CallExpr) string {
+	if len(call.Args) == 0 {
+		return ""
+	}
+
+	argType := w.pkg.TypeOf(call.Args[0])
+	if argType == nil {
+		return ""
+	}
+
+	return types.TypeString(argType, nil)
+}
+
+func (*lintUnhandledErrors) isTypeError(t types.Type) bool {
+	const errorTypeName = "_.error"
+
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Id() == errorTypeName
+}
+
 func (w *lintUnhandledErrors) getFunc(call *ast.CallExpr) (*types.Func, bool) {
 	sel, ok := call.Fun.(*ast.SelectorExpr)
 	if !ok {
@@ -177,21 +371,3 @@ func (w *lintUnhandledErrors) getFunc(call *ast.CallExpr) (*types.Func, bool) {
 
 	return fn, true
 }
-
-func (w *lintUnhandledErrors) isSafeFprintfToBuffer(call *ast.CallExpr) bool {
-	if len(call.Args) == 0 {
-		return false
-	}
-
-	fn, ok := w.getFunc(call)
-	if !ok || fn.FullName() != "fmt.Fprintf" {
-		return false
-	}
-
-	argType := w.pkg.TypeOf(call.Args[0])
-	if argType == nil {
-		return false
-	}
-
-	return astutils.IsPointerToPkgDotType(argType, "bytes", "Buffer") || astutils.IsPointerToPkgDotType(argType, "strings", "Builder")
-}
@@ -0,0 +1,133 @@
+package rule
+
+import (
+	"testing"
+
+	"github.com/mgechev/revive/lint"
+)
+
+func TestUnhandledErrorRule_Configure(t *testing.T) {
+	tests := []struct {
+		name      string
+		arguments lint.Arguments
+		wantErr   bool
+	}{
+		{
+			name:      "regexp patterns",
+			arguments: lint.Arguments{`^fmt\.Print`, `os\.Chdir`},
+		},
+		{
+			name: "options",
+			arguments: lint.Arguments{
+				`^fmt\.Print`,
+				map[string]any{
+					"checkBlankAssignments": true,
+					"check-defer":           false,
+					"checkgo":               true,
+					"ignore-funcs":          []any{"fmt.Fprint*(*strings.Builder)", "(*bytes.Buffer).Write", "(io.Writer).Write"},
+				},
+			},
+		},
+		{
+			name:      "empty regexp",
+			arguments: lint.Arguments{" "},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid argument type",
+			arguments: lint.Arguments{1},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid toggle",
+			arguments: lint.Arguments{map[string]any{"check-defer": "yes"}},
+			wantErr:   true,
+		},
+		{
+			name:      "unknown option",
+			arguments: lint.Arguments{map[string]any{"check-everything": true}},
+			wantErr:   true,
+		},
+		{
+			name:      "unqualified function",
+			arguments: lint.Arguments{map[string]any{"ignore-funcs": []any{"Close"}}},
+			wantErr:   true,
+		},
+		{
+			name:      "unbalanced receiver",
+			arguments: lint.Arguments{map[string]any{"ignore-funcs": []any{"(*bytes.Buffer.Write"}}},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rule UnhandledErrorRule
+			err := rule.Configure(tt.arguments)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Configure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnhandledErrorRule_Reconfigure(t *testing.T) {
+	var rule UnhandledErrorRule
+	err := rule.Configure(lint.Arguments{
+		`^fmt\.Print`,
+		map[string]any{
+			"check-blank-assignments": true,
+			"check-defer":             true,
+			"check-go":                true,
+			"ignore-funcs":            []any{"(io.Writer).Write"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rule.Configure(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if rule.checkBlankAssignments || rule.checkDefer || rule.checkGo || len(rule.ignoreList) > 0 || len(rule.ignoredFuncs) > 0 {
+		t.Errorf("got options %+v after reconfiguring the rule without arguments, want none", rule)
+	}
+}
+
+func TestParseIgnoredFunc(t *testing.T) {
+	tests := []struct {
+		entry         string
+		wantQualifier string
+		wantArgType   string
+		match         []string
+		noMatch       []string
+	}{
+		{entry: "fmt.Fprintf", wantQualifier: "fmt", match: []string{"Fprintf"}, noMatch: []string{"Fprint", "Fprintln"}},
+		{entry: "fmt.Fprint*(*strings.Builder)", wantQualifier: "fmt", wantArgType: "*strings.Builder", match: []string{"Fprint", "Fprintf", "Fprintln"}},
+		{entry: "(*bytes.Buffer).Write*", wantQualifier: "(*bytes.Buffer)", match: []string{"Write", "WriteString"}, noMatch: []string{"Read"}},
+		{entry: "(github.com/org/pkg.T).Close", wantQualifier: "(github.com/org/pkg.T)", match: []string{"Close"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			got, err := parseIgnoredFunc(tt.entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.qualifier != tt.wantQualifier || got.argType != tt.wantArgType {
+				t.Errorf("got qualifier %q and argument type %q, want %q and %q", got.qualifier, got.argType, tt.wantQualifier, tt.wantArgType)
+			}
+			for _, name := range tt.match {
+				if !got.name.MatchString(name) {
+					t.Errorf("%q does not match %s", tt.entry, name)
+				}
+			}
+			for _, name := range tt.noMatch {
+				if got.name.MatchString(name) {
+					t.Errorf("%q matches %s", tt.entry, name)
+				}
+			}
+		})
+	}
+}
@@ -15,22 +15,22 @@ import (
 type UnsecureURLSchemeRule struct{}
 
 // Apply applied the rule to the given file.
-func (*UnsecureURLSchemeRule) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
-	if file.IsTest() {
-		return nil // skip test files
-	}
+func (r *UnsecureURLSchemeRule) Apply(file *lint.File, arguments lint.Arguments) []lint.Failure {
+	return lint.ApplyNodeRule(r, file, arguments)
+}
 
-	var failures []lint.Failure
-	onFailure := func(failure lint.Failure) {
-		failures = append(failures, failure)
-	}
+// NodeTypes returns the types of the nodes checked by the rule.
+func (*UnsecureURLSchemeRule) NodeTypes() []ast.Node {
+	return []ast.Node{(*ast.BasicLit)(nil)}
+}
 
-	w := lintUnsecureURLSchemeRule{
-		onFailure: onFailure,
+// CheckNodes returns the function checking the nodes of the file.
+func (*UnsecureURLSchemeRule) CheckNodes(file *lint.File, _ lint.Arguments, onFailure func(lint.Failure)) func(ast.Node) {
+	if file.IsTest() {
+		return nil // skip test files
 	}
 
-	ast.Walk(w, file.AST)
-	return failures
+	return lintUnsecureURLSchemeRule{onFailure: onFailure}.check
 }
 
 // Name returns the rule name.
@@ -52,10 +52,10 @@ const (
 	lenURLPrefixWS   = len(urlPrefixWS)
 )
 
-func (w lintUnsecureURLSchemeRule) Visit(node ast.Node) ast.Visitor {
+func (w lintUnsecureURLSchemeRule) check(node ast.Node) {
 	n, ok := node.(*ast.BasicLit)
 	if !ok || n.Kind != token.STRING {
-		return w // not a string literal
+		return // not a string literal
 	}
 
 	value, _ := strconv.Unquote(n.Value) // n.Value has one of the following forms: "..." or `...`
@@ -70,15 +70,15 @@ func (w lintUnsecureURLSchemeRule) Visit(node ast.Node) ast.Visitor {
 		scheme = schemeWS
 		lenURLPrefix = lenURLPrefixWS
 	default:
-		return nil // not an URL or not an unsecure one
+		return // not an URL or not an unsecure one
 	}
 
 	if len(value) <= lenURLPrefix {
-		return nil // there is no host part in the string
+		return // there is no host part in the string
 	}
 
 	if strings.Contains(value, "localhost") || strings.Contains(value, "127.0.0.1") || strings.Contains(value, "0.0.0.0") || strings.Contains(value, "//::") {
-		return nil // do not fail on local URL
+		return // do not fail on local URL
 	}
 
 	w.onFailure(lint.Failure{
@@ -86,6 +86,4 @@ func (w lintUnsecureURLSchemeRule) Visit(node ast.Node) ast.Visitor {
 		Failure:    fmt.Sprintf("prefer secure protocol %s over %s in %s", scheme+"s", scheme, n.Value),
 		Node:       n,
 	})
-
-	return nil
 }
@@ -10,22 +10,22 @@ import (
 type UseAnyRule struct{}
 
 // Apply applies the rule to given file.
-func (*UseAnyRule) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
-	if !file.Pkg.IsAtLeastGoVersion(lint.Go118) {
-		return nil // nothing to do, the alias any was added in version 1.18
-	}
+func (r *UseAnyRule) Apply(file *lint.File, arguments lint.Arguments) []lint.Failure {
+	return lint.ApplyNodeRule(r, file, arguments)
+}
 
-	var failures []lint.Failure
+// NodeTypes returns the types of the nodes checked by the rule.
+func (*UseAnyRule) NodeTypes() []ast.Node {
+	return []ast.Node{(*ast.InterfaceType)(nil)}
+}
 
-	walker := lintUseAny{
-		onFailure: func(failure lint.Failure) {
-			failures = append(failures, failure)
-		},
+// CheckNodes returns the function checking the nodes of the file.
+func (*UseAnyRule) CheckNodes(file *lint.File, _ lint.Arguments, onFailure func(lint.Failure)) func(ast.Node) {
+	if !file.Pkg.IsAtLeastGoVersion(lint.Go118) {
+		return nil // nothing to do, the alias any was added in version 1.18
 	}
-	fileAst := file.AST
-	ast.Walk(walker, fileAst)
 
-	return failures
+	return lintUseAny{onFailure: onFailure}.check
 }
 
 // Name returns the rule name.
@@ -37,14 +37,10 @@ type lintUseAny struct {
 	onFailure func(lint.Failure)
 }
 
-func (w lintUseAny) Visit(n ast.Node) ast.Visitor {
-	it, ok := n.(*ast.InterfaceType)
-	if !ok {
-		return w
-	}
-
+func (w lintUseAny) check(n ast.Node) {
+	it := n.(*ast.InterfaceType)
 	if len(it.Methods.List) != 0 {
-		return w // it is not and empty interface
+		return // it is not and empty interface
 	}
 
 	w.onFailure(lint.Failure{
@@ -53,6 +49,4 @@ func (w lintUseAny) Visit(n ast.Node) ast.Visitor {
 		Category:   lint.FailureCategoryNaming,
 		Failure:    "since Go 1.18 'interface{}' can be replaced by 'any'",
 	})
-
-	return w
 }
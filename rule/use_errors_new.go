@@ -11,23 +11,23 @@ import (
 type UseErrorsNewRule struct{}
 
 // Apply applies the rule to given file.
-func (*UseErrorsNewRule) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
+func (r *UseErrorsNewRule) Apply(file *lint.File, arguments lint.Arguments) []lint.Failure {
+	return lint.ApplyNodeRule(r, file, arguments)
+}
+
+// NodeTypes returns the types of the nodes checked by the rule.
+func (*UseErrorsNewRule) NodeTypes() []ast.Node {
+	return []ast.Node{(*ast.CallExpr)(nil)}
+}
+
+// CheckNodes returns the function checking the nodes of the file.
+func (*UseErrorsNewRule) CheckNodes(file *lint.File, _ lint.Arguments, onFailure func(lint.Failure)) func(ast.Node) {
 	if file.Pkg.IsAtLeastGoVersion(lint.Go126) {
 		// For unformatted strings in Go 1.26, fmt.Errorf matches the behavior of errors.New, so we can skip the analysis.
 		return nil
 	}
 
-	var failures []lint.Failure
-
-	walker := lintFmtErrorf{
-		onFailure: func(failure lint.Failure) {
-			failures = append(failures, failure)
-		},
-	}
-
-	ast.Walk(walker, file.AST)
-
-	return failures
+	return lintFmtErrorf{onFailure: onFailure}.check
 }
 
 // Name returns the rule name.
@@ -39,19 +39,16 @@ type lintFmtErrorf struct {
 	onFailure func(lint.Failure)
 }
 
-func (w lintFmtErrorf) Visit(n ast.Node) ast.Visitor {
-	funcCall, ok := n.(*ast.CallExpr)
-	if !ok {
-		return w // not a function call
-	}
+func (w lintFmtErrorf) check(n ast.Node) {
+	funcCall := n.(*ast.CallExpr)
 
 	isFmtErrorf := astutils.IsPkgDotName(funcCall.Fun, "fmt", "Errorf")
 	if !isFmtErrorf {
-		return w // not a call to fmt.Errorf
+		return // not a call to fmt.Errorf
 	}
 
 	if len(funcCall.Args) > 1 {
-		return w // the use of fmt.Errorf is legit
+		return // the use of fmt.Errorf is legit
 	}
 
 	// the call is of the form fmt.Errorf("...")
@@ -61,6 +58,4 @@ func (w lintFmtErrorf) Visit(n ast.Node) ast.Visitor {
 		Confidence: 1,
 		Failure:    "replace fmt.Errorf by errors.New",
 	})
-
-	return w
 }
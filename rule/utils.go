@@ -1,6 +1,8 @@
 package rule
 
 import (
+	"bytes"
+	"go/ast"
 	"go/token"
 	"regexp"
 	"strings"
@@ -60,3 +62,26 @@ func isDirectiveComment(line string) bool {
 func newInternalFailureError(e error) []lint.Failure {
 	return []lint.Failure{lint.NewInternalFailure(e.Error())}
 }
+
+// replacementLine returns the line of the node with the node replaced, or an empty string
+// if the node spans several lines.
+//
+// The line is found from the byte offsets of the node, as the lines and columns of its position
+// follow the //line directives of the file.
+func replacementLine(file *lint.File, node ast.Node, replacement string) string {
+	content := file.Content()
+	start, end := file.ToPosition(node.Pos()).Offset, file.ToPosition(node.End()).Offset
+	if start < 0 || end < start || end > len(content) || bytes.IndexByte(content[start:end], '\n') >= 0 {
+		return ""
+	}
+
+	lineStart := bytes.LastIndexByte(content[:start], '\n') + 1
+	lineEnd := bytes.IndexByte(content[end:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(content)
+	} else {
+		lineEnd += end
+	}
+	line := string(content[lineStart:start]) + replacement + string(content[end:lineEnd])
+	return strings.TrimSuffix(line, "\r")
+}
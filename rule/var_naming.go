@@ -5,11 +5,11 @@ import (
 	"go/ast"
 	"go/token"
 	"strings"
+	"sync"
 
 	"github.com/mgechev/revive/internal/astutils"
 	"github.com/mgechev/revive/internal/rule"
 	"github.com/mgechev/revive/lint"
-	"github.com/mgechev/revive/logging"
 )
 
 var knownNameExceptions = map[string]bool{
@@ -24,12 +24,18 @@ type VarNamingRule struct {
 
 	allowUpperCaseConst      bool // if true - allows to use UPPER_SOME_NAMES for constants
 	skipInitialismNameChecks bool // if true - disable enforcing capitals for common initialisms
+
+	// warnings about the configuration are logged with the logger of the first linted file,
+	// as no logger is available when configuring the rule.
+	warnings     []string
+	warningsOnce sync.Once
 }
 
 // Configure validates the rule configuration, and configures the rule accordingly.
 //
 // Configuration implements the [lint.ConfigurableRule] interface.
 func (r *VarNamingRule) Configure(arguments lint.Arguments) error {
+	r.warnings = nil
 	if len(arguments) >= 1 {
 		list, err := getList(arguments[0], "allowlist")
 		if err != nil {
@@ -67,21 +73,12 @@ func (r *VarNamingRule) Configure(arguments lint.Arguments) error {
 			case isRuleOption(k, "upperCaseConst"):
 				r.allowUpperCaseConst = fmt.Sprint(v) == "true"
 			case isRuleOption(k, "skipPackageNameChecks"):
-				logger, err := logging.GetLogger()
-				if err == nil {
-					logger.Warn("The option var-naming.skipPackageNameChecks is no longer supported and will be ignored; use package-naming rule instead")
-				}
+				r.warnings = append(r.warnings, "The option var-naming.skipPackageNameChecks is no longer supported and will be ignored; use package-naming rule instead")
 			case isRuleOption(k, "extraBadPackageNames"):
-				logger, err := logging.GetLogger()
-				if err == nil {
-					logger.Warn("The option var-naming.extraBadPackageNames is no longer supported and will be ignored; use package-naming.userDefinedBadNames instead")
-				}
+				r.warnings = append(r.warnings, "The option var-naming.extraBadPackageNames is no longer supported and will be ignored; use package-naming.userDefinedBadNames instead")
 			case isRuleOption(k, "skipPackageNameCollisionWithGoStd"):
-				logger, err := logging.GetLogger()
-				if err == nil {
-					logger.Warn("The option var-naming.skipPackageNameCollisionWithGoStd is no longer supported and will be ignored; " +
-						"use package-naming.skipCollisionWithCommonStd instead (or package-naming.checkCollisionWithAllStd for the old 'all std' behavior)")
-				}
+				r.warnings = append(r.warnings, "The option var-naming.skipPackageNameCollisionWithGoStd is no longer supported and will be ignored; "+
+					"use package-naming.skipCollisionWithCommonStd instead (or package-naming.checkCollisionWithAllStd for the old 'all std' behavior)")
 			}
 		}
 	}
@@ -91,6 +88,12 @@ func (r *VarNamingRule) Configure(arguments lint.Arguments) error {
 
 // Apply applies the rule to given file.
 func (r *VarNamingRule) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
+	r.warningsOnce.Do(func() {
+		for _, warning := range r.warnings {
+			file.Logger().Warn(warning)
+		}
+	})
+
 	var failures []lint.Failure
 	onFailure := func(failure lint.Failure) {
 		failures = append(failures, failure)
@@ -11,16 +11,18 @@ import (
 type WaitGroupByValueRule struct{}
 
 // Apply applies the rule to given file.
-func (*WaitGroupByValueRule) Apply(file *lint.File, _ lint.Arguments) []lint.Failure {
-	var failures []lint.Failure
+func (r *WaitGroupByValueRule) Apply(file *lint.File, arguments lint.Arguments) []lint.Failure {
+	return lint.ApplyNodeRule(r, file, arguments)
+}
 
-	onFailure := func(failure lint.Failure) {
-		failures = append(failures, failure)
-	}
+// NodeTypes returns the types of the nodes checked by the rule.
+func (*WaitGroupByValueRule) NodeTypes() []ast.Node {
+	return []ast.Node{(*ast.FuncDecl)(nil)}
+}
 
-	w := lintWaitGroupByValueRule{onFailure: onFailure}
-	ast.Walk(w, file.AST)
-	return failures
+// CheckNodes returns the function checking the nodes of the file.
+func (*WaitGroupByValueRule) CheckNodes(_ *lint.File, _ lint.Arguments, onFailure func(lint.Failure)) func(ast.Node) {
+	return lintWaitGroupByValueRule{onFailure: onFailure}.check
 }
 
 // Name returns the rule name.
@@ -32,12 +34,8 @@ type lintWaitGroupByValueRule struct {
 	onFailure func(lint.Failure)
 }
 
-func (w lintWaitGroupByValueRule) Visit(node ast.Node) ast.Visitor {
-	// look for function declarations
-	fd, ok := node.(*ast.FuncDecl)
-	if !ok {
-		return w
-	}
+func (w lintWaitGroupByValueRule) check(node ast.Node) {
+	fd := node.(*ast.FuncDecl)
 
 	// Check all function parameters
 	for _, field := range fd.Type.Params.List {
@@ -51,6 +49,4 @@ func (w lintWaitGroupByValueRule) Visit(node ast.Node) ast.Visitor {
 			Failure:    "sync.WaitGroup passed by value, the function will get a copy of the original one",
 		})
 	}
-
-	return nil // skip visiting function body
 }
@@ -0,0 +1,11 @@
+package test_test
+
+import (
+	"testing"
+
+	"github.com/mgechev/revive/rule"
+)
+
+func TestContextPropagation(t *testing.T) {
+	testRule(t, "context_propagation", &rule.ContextPropagationRule{})
+}
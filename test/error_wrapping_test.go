@@ -0,0 +1,14 @@
+package test_test
+
+import (
+	"testing"
+
+	"github.com/mgechev/revive/rule"
+)
+
+func TestErrorWrapping(t *testing.T) {
+	testRule(t, "go1.25/error_wrapping", &rule.ErrorWrappingRule{})
+	testRule(t, "go1.18/error_wrapping", &rule.ErrorWrappingRule{})
+	testRule(t, "go1.25/error_wrapping_without_errors_import", &rule.ErrorWrappingRule{})
+	testRule(t, "go1.25/error_wrapping_line_directive", &rule.ErrorWrappingRule{})
+}
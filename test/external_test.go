@@ -0,0 +1,115 @@
+package test_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mgechev/revive/lint"
+	"github.com/mgechev/revive/rule"
+)
+
+const externalRuleProcessEnv = "REVIVE_TEST_EXTERNAL_RULE_PROCESS"
+
+func newExternalRule(t *testing.T) *rule.ExternalRule {
+	t.Helper()
+
+	r, err := rule.NewExternalRule("external-println", []string{os.Args[0], "-test.run=^TestExternalRuleProcess$"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(externalRuleProcessEnv, "report")
+	return r
+}
+
+func TestExternal(t *testing.T) {
+	testRule(t, "external", newExternalRule(t), &lint.RuleConfig{
+		Arguments: lint.Arguments{"println("},
+	})
+}
+
+func TestExternalFailingCommand(t *testing.T) {
+	r := newExternalRule(t)
+	t.Setenv(externalRuleProcessEnv, "fail")
+
+	// the failure of the command is logged, and the rule is skipped
+	testRule(t, "external_failing", r, &lint.RuleConfig{
+		Arguments: lint.Arguments{"println("},
+	})
+}
+
+func TestExternalInMemorySource(t *testing.T) {
+	r := newExternalRule(t)
+
+	// the file only exists in memory: the command gets its content in the request
+	filename := "../testdata/external_in_memory.go"
+	reader := func(path string) ([]byte, error) {
+		if path != filename {
+			return nil, os.ErrNotExist
+		}
+		return []byte("package fixtures\n\nfunc inMemory() {\n\tprintln(\"unsaved\")\n}\n"), nil
+	}
+
+	config := lint.Config{
+		Confidence: 0.8,
+		Rules:      lint.RulesConfig{r.Name(): {Arguments: lint.Arguments{"println("}}},
+	}
+	linter := lint.New(reader, 0)
+	failures, err := linter.Lint([][]string{{filename}}, []lint.Rule{r}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for failure := range failures {
+		got = append(got, fmt.Sprintf("%d: %s", failure.Position.Start.Line, failure.Failure))
+	}
+	if len(got) != 1 || got[0] != "4: avoid println(" {
+		t.Errorf("expected the failure found in the in-memory source, got %q", got)
+	}
+}
+
+// TestExternalRuleProcess is not a real test: it is the external command run by the rule in the tests above.
+// It reports the lines containing the first argument of the rule.
+func TestExternalRuleProcess(*testing.T) {
+	switch os.Getenv(externalRuleProcessEnv) {
+	case "report":
+		if err := reportExternalRuleFailures(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1) //revive:disable-line:deep-exit
+		}
+		os.Exit(0) //revive:disable-line:deep-exit
+	case "fail":
+		fmt.Fprintln(os.Stderr, "simulated failure")
+		os.Exit(1) //revive:disable-line:deep-exit
+	}
+}
+
+func reportExternalRuleFailures() error {
+	var request rule.ExternalRuleRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		return err
+	}
+	needle, _ := request.Arguments[0].(string)
+
+	failures := []lint.Failure{}
+	for _, filename := range request.Files {
+		src, ok := request.Sources[filename]
+		if !ok {
+			return fmt.Errorf("missing source of %s", filename)
+		}
+		for i, line := range strings.Split(src, "\n") {
+			if column := strings.Index(line, needle); column >= 0 {
+				failure := lint.Failure{Failure: "avoid " + needle, Confidence: 1}
+				failure.Position.Start.Filename = filename
+				failure.Position.Start.Line = i + 1
+				failure.Position.Start.Column = column + 1
+				failures = append(failures, failure)
+			}
+		}
+	}
+
+	return json.NewEncoder(os.Stdout).Encode(failures)
+}
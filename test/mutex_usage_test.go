@@ -0,0 +1,11 @@
+package test_test
+
+import (
+	"testing"
+
+	"github.com/mgechev/revive/rule"
+)
+
+func TestMutexUsage(t *testing.T) {
+	testRule(t, "mutex_usage", &rule.MutexUsageRule{})
+}
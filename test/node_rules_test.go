@@ -0,0 +1,120 @@
+package test_test
+
+import (
+	"go/ast"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/mgechev/revive/lint"
+	"github.com/mgechev/revive/rule"
+)
+
+func nodeRules() []lint.Rule {
+	return []lint.Rule{
+		&rule.ContextKeysType{},
+		&rule.TimeEqualRule{},
+		&rule.UnsecureURLSchemeRule{},
+		&rule.UseAnyRule{},
+		&rule.UseErrorsNewRule{},
+		&rule.WaitGroupByValueRule{},
+	}
+}
+
+// walkingRule applies a node rule walking the file on its own,
+// as rules not implementing lint.NodeRule do.
+type walkingRule struct {
+	rule lint.NodeRule
+}
+
+func (r walkingRule) Name() string { return r.rule.Name() }
+
+func (r walkingRule) Apply(file *lint.File, arguments lint.Arguments) []lint.Failure {
+	var failures []lint.Failure
+	check := r.rule.CheckNodes(file, arguments, func(failure lint.Failure) {
+		failures = append(failures, failure)
+	})
+	if check == nil {
+		return nil
+	}
+
+	var types []reflect.Type
+	for _, nodeType := range r.rule.NodeTypes() {
+		types = append(types, reflect.TypeOf(nodeType))
+	}
+	ast.Inspect(file.AST, func(node ast.Node) bool {
+		if node != nil && slices.Contains(types, reflect.TypeOf(node)) {
+			check(node)
+		}
+		return true
+	})
+	return failures
+}
+
+func walkingRules() []lint.Rule {
+	var rules []lint.Rule
+	for _, r := range nodeRules() {
+		rules = append(rules, walkingRule{r.(lint.NodeRule)})
+	}
+	return rules
+}
+
+// lintTestdata lints each file of testdata as a package, and returns the failures as strings.
+func lintTestdata(tb testing.TB, rules []lint.Rule) []string {
+	tb.Helper()
+
+	filenames, err := filepath.Glob(filepath.Join("..", "testdata", "*.go"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	packages := make([][]string, len(filenames))
+	for i, filename := range filenames {
+		packages[i] = []string{filename}
+	}
+
+	config := lint.Config{Rules: lint.RulesConfig{}}
+	for _, r := range rules {
+		config.Rules[r.Name()] = lint.RuleConfig{}
+	}
+	linter := lint.New(os.ReadFile, 0)
+	failures, err := linter.Lint(packages, rules, config)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	var result []string
+	for failure := range failures {
+		result = append(result, failure.Position.Start.String()+": "+failure.RuleName+": "+failure.Failure)
+	}
+	slices.Sort(result)
+	return result
+}
+
+func TestNodeRules_sharedTraversal(t *testing.T) {
+	want := lintTestdata(t, walkingRules())
+	if len(want) == 0 {
+		t.Fatal("expected failures in testdata")
+	}
+
+	got := lintTestdata(t, nodeRules())
+	if !slices.Equal(got, want) {
+		t.Errorf("the shared traversal found %d failures, %d walking the files once per rule", len(got), len(want))
+	}
+}
+
+// BenchmarkNodeRules compares the node rules walking the files of testdata once per rule
+// and in a single shared traversal.
+func BenchmarkNodeRules(b *testing.B) {
+	b.Run("walking once per rule", func(b *testing.B) {
+		for b.Loop() {
+			lintTestdata(b, walkingRules())
+		}
+	})
+	b.Run("shared traversal", func(b *testing.B) {
+		for b.Loop() {
+			lintTestdata(b, nodeRules())
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package test_test
+
+import (
+	"testing"
+
+	"github.com/mgechev/revive/lint"
+	"github.com/mgechev/revive/rule"
+)
+
+func TestPattern(t *testing.T) {
+	r, err := rule.NewPatternRule("sprintf-string", lint.PatternRuleConfig{
+		Pattern:     `fmt.Sprintf("%s", $x)`,
+		Where:       map[string]string{"x": "string"},
+		Message:     "use $x instead of fmt.Sprintf",
+		Replacement: "$x",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testRule(t, "pattern", r)
+}
+
+func TestPatternList(t *testing.T) {
+	r, err := rule.NewPatternRule("errors-new-sprintf", lint.PatternRuleConfig{
+		Pattern:     `errors.New(fmt.Sprintf($*args))`,
+		Message:     "use fmt.Errorf($args) instead of errors.New(fmt.Sprintf(...))",
+		Replacement: "fmt.Errorf($args)",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testRule(t, "pattern_list", r)
+}
@@ -0,0 +1,18 @@
+package test_test
+
+import (
+	"testing"
+
+	"github.com/mgechev/revive/lint"
+	"github.com/mgechev/revive/rule"
+)
+
+func TestUnclosedResource(t *testing.T) {
+	testRule(t, "unclosed_resource", &rule.UnclosedResourceRule{})
+}
+
+func TestUnclosedResourceWithResources(t *testing.T) {
+	testRule(t, "unclosed_resource_w_resources", &rule.UnclosedResourceRule{}, &lint.RuleConfig{
+		Arguments: lint.Arguments{"(*time.Timer).Stop", "(*fixtures.pool).Release"},
+	})
+}
@@ -1,6 +1,8 @@
 package test_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/mgechev/revive/lint"
@@ -11,6 +13,17 @@ func TestUnhandledError(t *testing.T) {
 	testRule(t, "unhandled_error", &rule.UnhandledErrorRule{})
 }
 
+func TestUnhandledErrorUnconfigured(t *testing.T) {
+	// the writes to memory buffers are ignored even if the rule is not configured
+	path := filepath.Join("..", "testdata", "unhandled_error.go")
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertFailures(t, path, []lint.Rule{&rule.UnhandledErrorRule{}}, lint.Config{}, parseInstructions(t, path, src))
+}
+
 func TestUnhandledErrorWithIgnoreList(t *testing.T) {
 	testRule(t, "unhandled_error_w_ignorelist", &rule.UnhandledErrorRule{}, &lint.RuleConfig{
 		Arguments: lint.Arguments{
@@ -23,3 +36,21 @@ func TestUnhandledErrorWithIgnoreList(t *testing.T) {
 		},
 	})
 }
+
+func TestUnhandledErrorWithOptions(t *testing.T) {
+	testRule(t, "unhandled_error_w_options", &rule.UnhandledErrorRule{}, &lint.RuleConfig{
+		Arguments: lint.Arguments{
+			map[string]any{
+				"check-blank-assignments": true,
+				"check-defer":             true,
+				"check-go":                true,
+				"ignore-funcs": []any{
+					"(io.Writer).Write",
+					"(*bytes.Buffer).Write*",
+					"fmt.Fprint*(*strings.Builder)",
+					"fixtures.unhandledErrorOptionsHelper",
+				},
+			},
+		},
+	})
+}
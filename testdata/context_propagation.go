@@ -0,0 +1,114 @@
+package fixtures
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+type store struct {
+	db *sql.DB
+}
+
+func (s *store) find(ctx context.Context, id int) error {
+	_, err := s.db.Query("SELECT 1") // MATCH /call to s.db.Query in a function receiving the context ctx, use s.db.QueryContext to pass it/
+	if err != nil {
+		return err
+	}
+	_, err = s.db.QueryContext(ctx, "SELECT 1")
+
+	bg := context.Background() // MATCH /context.Background() creates a new context in a function receiving the context ctx, use ctx instead/ -> `	bg := ctx`
+	_ = bg
+
+	go func() {
+		s.refresh(context.TODO()) // MATCH /context.TODO() creates a new context in a function receiving the context ctx, use ctx instead/ -> `		s.refresh(ctx)`
+	}()
+
+	return err
+}
+
+func (s *store) refresh(_ context.Context) {
+	s.refresh(context.Background())
+}
+
+func (s *store) sync() {}
+
+func (s *store) syncContext(ctx context.Context) {}
+
+func newRequests(ctx context.Context) {
+	http.NewRequest("GET", "/", nil) // MATCH /call to http.NewRequest in a function receiving the context ctx, use http.NewRequestWithContext to pass it/
+	http.NewRequestWithContext(ctx, "GET", "/", nil)
+	http.Get("/")
+}
+
+func withoutContext() {
+	ctx := context.Background()
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	_ = timeoutCtx
+
+	leaked, leakedCancel := context.WithCancel(ctx) // MATCH /the cancel function leakedCancel returned by context.WithCancel is never called, the context leaks until its parent is canceled/
+	_ = leaked
+
+	discarded, _ := context.WithDeadline(ctx, time.Now()) // MATCH /the cancel function returned by context.WithDeadline is discarded, the context leaks until its parent is canceled/
+	_ = discarded
+
+	var stored context.CancelFunc
+	ctx, stored = context.WithCancel(ctx)
+	go stored()
+
+	ctx, cancel = context.WithCancel(ctx) // MATCH /the cancel function cancel returned by context.WithCancel is never called, the context leaks until its parent is canceled/
+
+	var returned context.CancelFunc
+	ctx, returned = context.WithCancel(ctx)
+	keep(returned)
+
+	var s store
+	s.sync() // no context to pass
+	s.syncContext(ctx)
+
+	func(ctx context.Context) {
+		s.sync() // MATCH /call to s.sync in a function receiving the context ctx, use s.syncContext to pass it/
+	}(ctx)
+}
+
+func cancelInLoop(ctx context.Context) {
+	var cancel context.CancelFunc
+	for range 3 {
+		if cancel != nil {
+			cancel()
+		}
+		ctx, cancel = context.WithCancel(ctx)
+	}
+}
+
+func keep(context.CancelFunc) {}
+
+func optionalTimeout(ctx context.Context, d time.Duration) {
+	var cancel context.CancelFunc
+	defer func() {
+		if cancel != nil {
+			cancel()
+		}
+	}()
+	if d > 0 {
+		ctx, cancel = context.WithTimeout(ctx, d)
+	}
+	_ = ctx
+}
+
+func cancelAssignedInClosure(ctx context.Context) {
+	var cancel context.CancelFunc
+	func() {
+		ctx, cancel = context.WithCancel(ctx)
+	}()
+	cancel()
+
+	func() {
+		cancel()
+		ctx, cancel = context.WithCancel(ctx) // MATCH /the cancel function cancel returned by context.WithCancel is never called, the context leaks until its parent is canceled/
+	}()
+	_ = ctx
+}
@@ -0,0 +1,7 @@
+package fixtures
+
+func external() {
+	println("reported") // MATCH /avoid println(/
+	println("disabled") //revive:disable-line:external-println
+	print("not matched")
+}
@@ -0,0 +1,5 @@
+package fixtures
+
+func externalFailing() {
+	println("not reported, the external command fails")
+}
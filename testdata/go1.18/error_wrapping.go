@@ -0,0 +1,19 @@
+package fixtures
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errNotFound = errors.New("not found")
+
+func errorWrapping(name string, err error) error {
+	_ = fmt.Errorf("open %s: %v", name, err) // MATCH /fmt.Errorf formats the error err with %v, use %w to wrap it so that errors.Is and errors.As can inspect it/ -> `	_ = fmt.Errorf("open %s: %w", name, err)`
+
+	_ = fmt.Errorf("%v and %v", err, errNotFound) // MATCH /fmt.Errorf formats the error err with %v, use %w to wrap it so that errors.Is and errors.As can inspect it/ -> `	_ = fmt.Errorf("%w and %v", err, errNotFound)`
+
+	_ = fmt.Errorf("%w and %v", err, errNotFound)
+
+	_ = fmt.Errorf("%w and %w", err, errNotFound) // MATCH /fmt.Errorf calls with several %w verbs require Go 1.20/
+	return nil
+}
@@ -0,0 +1,81 @@
+package fixtures
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"example.com/missing"
+)
+
+var errNotFound = errors.New("not found")
+
+// unknownError embeds a type from a package that cannot be imported
+type unknownError struct{ *missing.Error }
+
+type notFoundError struct{ name string }
+
+func (e *notFoundError) Error() string { return e.name + " not found" }
+
+// Is compares errors without errors.Is
+func (e *notFoundError) Is(target error) bool {
+	return target == errNotFound
+}
+
+func errorWrapping(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	_ = fmt.Errorf("open %s: %v", name, err) // MATCH /fmt.Errorf formats the error err with %v, use %w to wrap it so that errors.Is and errors.As can inspect it/ -> `	_ = fmt.Errorf("open %s: %w", name, err)`
+
+	_ = fmt.Errorf("open: %+v", err) // MATCH /fmt.Errorf formats the error err with %+v, use %w to wrap it so that errors.Is and errors.As can inspect it/ -> `	_ = fmt.Errorf("open: %w", err)`
+
+	_ = fmt.Errorf(`%s: %s`, name, err) // MATCH /fmt.Errorf formats the error err with %s, use %w to wrap it so that errors.Is and errors.As can inspect it/
+
+	_ = fmt.Errorf("%w and %v", err, io.EOF) // MATCH /fmt.Errorf formats the error io.EOF with %v, use %w to wrap it so that errors.Is and errors.As can inspect it/ -> `	_ = fmt.Errorf("%w and %w", err, io.EOF)`
+
+	_ = fmt.Errorf("%[2]v: %[1]s", name, err) // MATCH /fmt.Errorf formats the error err with %[2]v, use %w to wrap it so that errors.Is and errors.As can inspect it/
+
+	_ = fmt.Errorf("%*d: %v", 3, 1, &notFoundError{}) // MATCH /fmt.Errorf formats the error &notFoundError{} with %v, use %w to wrap it so that errors.Is and errors.As can inspect it/
+
+	_ = fmt.Errorf("open %s: %w", name, err)
+
+	_ = fmt.Errorf("%d%%: %v", 1, err.Error())
+
+	_ = fmt.Errorf("%s", name)
+
+	var unknown *unknownError
+	_ = fmt.Errorf("%v", unknown) // unknown methods
+
+	if err == errNotFound { // MATCH /comparing errors with == fails on wrapped errors, use errors.Is(err, errNotFound)/ -> `	if errors.Is(err, errNotFound) {`
+		return nil
+	}
+	if err != io.EOF { // io.Reader implementations return io.EOF unwrapped
+		return err
+	}
+	if err == io.ErrUnexpectedEOF || (io.EOF) == err {
+		return err
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	var nf *notFoundError
+	if nf == (&notFoundError{}) {
+		return nil
+	}
+
+	if nf, ok := err.(*notFoundError); ok { // MATCH /type assertion on error err fails on wrapped errors, use errors.As/
+		return nf
+	}
+	switch e := err.(type) { // MATCH /type switch on error err fails on wrapped errors, use errors.As/
+	case *notFoundError:
+		return fmt.Errorf("%v", e) // MATCH /fmt.Errorf formats the error e with %v, use %w to wrap it so that errors.Is and errors.As can inspect it/ -> `		return fmt.Errorf("%w", e)`
+	}
+	var v any = err
+	if _, ok := v.(*notFoundError); ok {
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package fixtures
+
+import "errors"
+
+var errLineDirective = errors.New("line directive")
+
+func lineDirective(err error) bool {
+//line generated.go:9:40
+	return err == errLineDirective // MATCH /comparing errors with == fails on wrapped errors, use errors.Is(err, errLineDirective)/ -> `	return errors.Is(err, errLineDirective)`
+}
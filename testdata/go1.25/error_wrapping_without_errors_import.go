@@ -0,0 +1,7 @@
+package fixtures
+
+import "os"
+
+func errorWrappingWithoutErrorsImport(err error) bool {
+	return err == os.ErrNotExist // MATCH /comparing errors with == fails on wrapped errors, use errors.Is(err, os.ErrNotExist) and import errors/
+}
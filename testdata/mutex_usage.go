@@ -0,0 +1,298 @@
+package fixtures
+
+import (
+	"errors"
+	"sync"
+)
+
+type counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+type cache struct {
+	mu      sync.RWMutex
+	entries map[string]string
+}
+
+type lazy struct {
+	once  sync.Once
+	value int
+}
+
+type shard struct {
+	counters [2]counter
+}
+
+func (c *counter) incr() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n++
+}
+
+func (c *counter) add(n int) error {
+	c.mu.Lock()
+	if n < 0 {
+		c.mu.Unlock()
+		return errors.New("negative")
+	}
+	c.n += n
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *counter) addOrFail(n int) error {
+	c.mu.Lock() // MATCH /c.mu.Lock() is not followed by c.mu.Unlock() on all the paths of the function, consider defer c.mu.Unlock()/
+	if n < 0 {
+		return errors.New("negative")
+	}
+	c.n += n
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *counter) reset() {
+	c.mu.Lock() // MATCH /c.mu.Lock() is not followed by c.mu.Unlock() on all the paths of the function, consider defer c.mu.Unlock()/
+	c.n = 0
+}
+
+func (c *counter) resetIf(reset bool) {
+	if reset {
+		c.mu.Lock()
+		c.n = 0
+	}
+	c.mu.Unlock()
+}
+
+func (c *counter) resetAsync() {
+	c.mu.Lock()
+	go func() {
+		defer c.mu.Unlock()
+		c.n = 0
+	}()
+}
+
+func (c *counter) mustPositive() {
+	c.mu.Lock()
+	if c.n < 0 {
+		panic("negative")
+	}
+	c.mu.Unlock()
+}
+
+func (c *counter) switchAdd(n int) int {
+	c.mu.Lock() // MATCH /c.mu.Lock() is not followed by c.mu.Unlock() on all the paths of the function, consider defer c.mu.Unlock()/
+	switch {
+	case n > 0:
+		c.n += n
+	case n < 0:
+		return c.n
+	}
+	c.mu.Unlock()
+	return n
+}
+
+func (c *counter) lockShard() {
+	c.mu.Lock() // MATCH /c.mu.Lock() is not followed by c.mu.Unlock() on all the paths of the function, consider defer c.mu.Unlock()/
+}
+
+func (c *counter) UpdateWithLock() {
+	c.mu.Lock() // MATCH /c.mu.Lock() is not followed by c.mu.Unlock() on all the paths of the function, consider defer c.mu.Unlock()/
+	c.n++
+}
+
+func (c *counter) lockedCopy() int {
+	c.mu.Lock() // MATCH /c.mu.Lock() is not followed by c.mu.Unlock() on all the paths of the function, consider defer c.mu.Unlock()/
+	return c.n
+}
+
+func (c *counter) waitZero(ch chan int) {
+	c.mu.Lock()
+	for {
+		if <-ch == 0 {
+			c.mu.Unlock()
+			return
+		}
+	}
+}
+
+func (c *counter) switchBreak(n int) {
+	c.mu.Lock() // MATCH /c.mu.Lock() is not followed by c.mu.Unlock() on all the paths of the function, consider defer c.mu.Unlock()/
+	switch {
+	case n < 0:
+		break
+	default:
+		c.n = n
+		c.mu.Unlock()
+	}
+}
+
+func (c *counter) decr() {
+	defer c.mu.Unlock() // MATCH /defer c.mu.Unlock() is placed before c.mu.Lock(), the lock could be released before being acquired; defer it after locking/
+	c.mu.Lock()
+	c.n--
+}
+
+func (c *cache) get(key string) string {
+	c.mu.RLock()
+	defer c.mu.Unlock() // MATCH /c.mu.RLock() paired with c.mu.Unlock(), use c.mu.RUnlock()/
+	return c.entries[key]
+}
+
+func (c *cache) set(key, value string) {
+	c.mu.Lock()
+	c.entries[key] = value
+	c.mu.RUnlock() // MATCH /c.mu.Lock() paired with c.mu.RUnlock(), use c.mu.Unlock()/
+}
+
+func (c *cache) lookup(key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	value, ok := c.entries[key]
+	return value, ok
+}
+
+func setAll(caches []*cache, key, value string) {
+	for _, c := range caches {
+		c.mu.Lock()
+		defer c.mu.Unlock() // MATCH /defer c.mu.Unlock() in a loop runs when the function returns, not at the end of the iteration/
+		c.entries[key] = value
+	}
+
+	for _, c := range caches {
+		func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.entries[key] = value
+		}()
+	}
+}
+
+func (c counter) value() int { // MATCH /receiver c of type counter contains a sync.Mutex and is passed by value, use a pointer receiver/
+	return c.n
+}
+
+func (l lazy) get() int { // MATCH /receiver l of type lazy contains a sync.Once and is passed by value, use a pointer receiver/
+	l.once.Do(func() { l.value = 1 })
+	return l.value
+}
+
+func total(s shard) int { // MATCH /parameter s of type shard contains a sync.Mutex and is passed by value, use a pointer/
+	return s.counters[0].n + s.counters[1].n
+}
+
+func totalPtr(s *shard) int {
+	return s.counters[0].n + s.counters[1].n
+}
+
+var process = func(mu sync.Mutex) {} // MATCH /parameter mu of type sync.Mutex contains a sync.Mutex and is passed by value, use a pointer/
+
+func signal(sync.Cond) {} // MATCH /parameter of type sync.Cond contains a sync.Cond and is passed by value, use a pointer/
+
+func sum(counters []counter) int {
+	n := 0
+	for _, c := range counters { // MATCH /range variable c copies values of type counter containing a sync.Mutex, range over the indexes or use pointers/
+		n += c.n
+	}
+	for i := range counters {
+		n += counters[i].n
+	}
+	return n
+}
+
+func clone(c *counter) *shard {
+	return &shard{
+		counters: [2]counter{
+			*c, // MATCH /composite literal copies *c of type counter containing a sync.Mutex, use a pointer/
+			{n: c.n},
+		},
+	}
+}
+
+func (c *counter) signalLast(last bool) {
+	for {
+		if last {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.n = 0
+			return
+		}
+	}
+}
+
+// reload is called with c.mu held.
+func (c *counter) reload(load func() int) int {
+	c.mu.Unlock()
+	n := load()
+	c.mu.Lock()
+	if n < 0 {
+		return 0
+	}
+	c.n = n
+	return n
+}
+
+func (c *counter) fetch(load func() int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.Unlock()
+	n := load()
+	c.mu.Lock()
+	return n
+}
+
+// acquireFirst returns the first counter of the shard, locked.
+func (s *shard) acquireFirst() (c *counter) {
+	c = &s.counters[0]
+	c.mu.Lock()
+	return
+}
+
+func (s *shard) first() *counter {
+	c := &s.counters[0]
+	c.mu.Lock()
+	return c
+}
+
+func (c *counter) addAll(ns []int) {
+	for _, n := range ns {
+		c.mu.Lock() // MATCH /c.mu.Lock() is not followed by c.mu.Unlock() on all the paths of the function, consider defer c.mu.Unlock()/
+		if n == 0 {
+			continue
+		}
+		c.n += n
+		c.mu.Unlock()
+	}
+}
+
+func (c *counter) addUntilZero(ns []int) {
+	for _, n := range ns {
+		c.mu.Lock() // MATCH /c.mu.Lock() is not followed by c.mu.Unlock() on all the paths of the function, consider defer c.mu.Unlock()/
+		if n == 0 {
+			break
+		}
+		c.n += n
+		c.mu.Unlock()
+	}
+}
+
+func (c *counter) addNonZero(ns []int) {
+	for _, n := range ns {
+		c.mu.Lock() // MATCH /c.mu.Lock() is not followed by c.mu.Unlock() on all the paths of the function, consider defer c.mu.Unlock()/
+		if n != 0 {
+			c.n += n
+			c.mu.Unlock()
+		}
+	}
+}
+
+func (c *counter) drain(load func() int) {
+	c.mu.Lock()
+	for c.n > 0 {
+		c.mu.Unlock()
+		n := load()
+		c.mu.Lock()
+		c.n -= n
+	}
+	c.mu.Unlock()
+}
@@ -0,0 +1,10 @@
+package fixtures
+
+import "fmt"
+
+func pattern(name string, n int, err error) string {
+	_ = fmt.Sprintf("%s", n)
+	_ = fmt.Sprintf("%s", err)
+	_ = fmt.Sprintf("%s", name)    //revive:disable-line:sprintf-string
+	return fmt.Sprintf("%s", name) // MATCH /use name instead of fmt.Sprintf/ -> `	return name`
+}
@@ -0,0 +1,13 @@
+package fixtures
+
+import (
+	"errors"
+	"fmt"
+)
+
+func patternList(id int, name string) error {
+	if id < 0 {
+		return errors.New(fmt.Sprintf("invalid id %d for %s", id, name)) // MATCH /use fmt.Errorf("invalid id %d for %s", id, name) instead of errors.New(fmt.Sprintf(...))/ -> `		return fmt.Errorf("invalid id %d for %s", id, name)`
+	}
+	return errors.New(fmt.Sprint(name))
+}
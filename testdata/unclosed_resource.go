@@ -0,0 +1,273 @@
+package fixtures
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"example.com/missing"
+)
+
+func deferredClose(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.ReadAll(f)
+	return err
+}
+
+func neverClosed(name string) ([]byte, error) {
+	f, err := os.Open(name) // MATCH /f returned by os.Open is not released on all paths, call f.Close(), return it or pass it elsewhere/
+	if err != nil {
+		return nil, err
+	}
+	var buf [16]byte
+	n, err := f.Read(buf[:])
+	return buf[:n], err
+}
+
+func closedOnSomePaths(name string, check bool) error {
+	f, err := os.Create(name) // MATCH /f returned by os.Create is not released on all paths, call f.Close(), return it or pass it elsewhere/
+	if err != nil {
+		return err
+	}
+	if check {
+		return errors.New("early return")
+	}
+	return f.Close()
+}
+
+func closedInBothBranches(name string, check bool) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if check {
+		f.Close()
+	} else {
+		return f.Close()
+	}
+	return nil
+}
+
+func returned(name string) (*os.File, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func passedElsewhere(name string) *bufio.Reader {
+	f, _ := os.Open(name)
+	return bufio.NewReader(f)
+}
+
+func capturedInClosure(name string) func() {
+	f, _ := os.Open(name)
+	return func() { f.Close() }
+}
+
+func responseBody(url string) (int, error) {
+	resp, err := http.Get(url) // MATCH /resp returned by http.Get is not released on all paths, call resp.Body.Close(), return it or pass it elsewhere/
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+func responseBodyClosed(url string) (int, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func rows(db *sql.DB) error {
+	rows, err := db.Query("SELECT 1") // MATCH /rows returned by db.Query is not released on all paths, call rows.Close(), return it or pass it elsewhere/
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+	return rows.Close()
+}
+
+func ticker(done chan struct{}) {
+	t := time.NewTicker(time.Second) // MATCH /t returned by time.NewTicker is not released on all paths, call t.Stop(), return it or pass it elsewhere/
+	for {
+		select {
+		case <-t.C:
+		case <-done:
+			return
+		}
+	}
+}
+
+func tickerStopped(done chan struct{}) {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+	<-done
+}
+
+func switchClose(name string, kind int) {
+	f, err := os.Open(name) // MATCH /f returned by os.Open is not released on all paths, call f.Close(), return it or pass it elsewhere/
+	if err != nil {
+		panic(err)
+	}
+	switch kind {
+	case 0:
+		f.Close()
+	case 1:
+		f.Close()
+	}
+}
+
+func switchCloseWithDefault(name string, kind int) {
+	f, err := os.Open(name)
+	if err != nil {
+		os.Exit(1)
+	}
+	switch kind {
+	case 0:
+		f.Close()
+	default:
+		f.Close()
+	}
+}
+
+func nilCheck(name string) {
+	f, _ := os.Open(name)
+	if f == nil {
+		return
+	}
+	f.Close()
+}
+
+func discarded(name string) {
+	_, err := os.Open(name) // MATCH /the resource returned by os.Open is discarded without being released/
+	_ = err
+}
+
+func notAResource() {
+	r := strings.NewReader("")
+	_ = r
+
+	u := newUnknown() // unknown methods
+	_ = u
+}
+
+// unknownResource embeds a type from a package that cannot be imported
+type unknownResource struct{ *missing.Conn }
+
+func newUnknown() *unknownResource { return nil }
+
+func inLoop(names []string) {
+	for _, name := range names {
+		f, err := os.Open(name) // MATCH /f returned by os.Open is not released on all paths, call f.Close(), return it or pass it elsewhere/
+		if err != nil {
+			continue
+		}
+		f.Name()
+	}
+}
+
+func infiniteLoop(name string, ch chan int) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	for {
+		if <-ch == 0 {
+			return f.Close()
+		}
+	}
+}
+
+func infiniteLoopWithBreak(name string, ch chan int) error {
+	f, err := os.Open(name) // MATCH /f returned by os.Open is not released on all paths, call f.Close(), return it or pass it elsewhere/
+	if err != nil {
+		return err
+	}
+	for {
+		switch <-ch {
+		case 0:
+			return f.Close()
+		case 1:
+			continue
+		}
+		break
+	}
+	return nil
+}
+
+func switchBreak(name string, kind int) error {
+	f, err := os.Open(name) // MATCH /f returned by os.Open is not released on all paths, call f.Close(), return it or pass it elsewhere/
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case 0:
+		if f.Name() == "" {
+			break
+		}
+		return f.Close()
+	default:
+		return f.Close()
+	}
+	return nil
+}
+
+func continueInLoop(names []string) {
+	for _, name := range names {
+		f, err := os.Open(name) // MATCH /f returned by os.Open is not released on all paths, call f.Close(), return it or pass it elsewhere/
+		if err != nil {
+			continue
+		}
+		if name == "x" {
+			continue
+		}
+		f.Close()
+	}
+}
+
+func breakInLoop(names []string) {
+	for _, name := range names {
+		f, err := os.Open(name) // MATCH /f returned by os.Open is not released on all paths, call f.Close(), return it or pass it elsewhere/
+		if err != nil {
+			continue
+		}
+		if name == "x" {
+			break
+		}
+		f.Close()
+	}
+}
+
+func continueAfterClose(names []string) {
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			continue
+		}
+		if name == "x" {
+			f.Close()
+			continue
+		}
+		f.Close()
+	}
+}
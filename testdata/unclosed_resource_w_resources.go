@@ -0,0 +1,20 @@
+package fixtures
+
+import "time"
+
+type pool struct{}
+
+func (*pool) Release() {}
+
+func newPool() *pool { return &pool{} }
+
+func resources() {
+	timer := time.NewTimer(time.Second) // MATCH /timer returned by time.NewTimer is not released on all paths, call timer.Stop(), return it or pass it elsewhere/
+	<-timer.C
+
+	p := newPool()
+	defer p.Release()
+
+	leaked := newPool() // MATCH /leaked returned by newPool is not released on all paths, call leaked.Release(), return it or pass it elsewhere/
+	_ = leaked == nil
+}
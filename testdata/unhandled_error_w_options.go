@@ -0,0 +1,54 @@
+package fixtures
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	str "strings"
+)
+
+func unhandledErrorOptions(w io.Writer, f *os.File) (int, error) {
+	_ = os.Chdir("..")           // MATCH /Unhandled error in call to function os.Chdir, assigned to the blank identifier/
+	n, _ := fmt.Println("")      // MATCH /Unhandled error in call to function fmt.Println, assigned to the blank identifier/
+	_, _ = fmt.Println("")       // MATCH /Unhandled error in call to function fmt.Println, assigned to the blank identifier/
+	_, err := fmt.Println("")    // not discarded
+	_, _ = n, os.Chdir("..")     // MATCH /Unhandled error in call to function os.Chdir, assigned to the blank identifier/
+	var _ = os.Remove("file")    // MATCH /Unhandled error in call to function os.Remove, assigned to the blank identifier/
+	_ = len("not an error")      // not an error
+	_, _ = io.WriteString(w, "") // MATCH /Unhandled error in call to function io.WriteString, assigned to the blank identifier/
+
+	defer f.Close()             // MATCH /Unhandled error in deferred call to function os.File.Close/
+	defer fmt.Println("")       // MATCH /Unhandled error in deferred call to function fmt.Println/
+	defer func() { f.Sync() }() // MATCH /Unhandled error in call to function os.File.Sync/
+	go os.Remove("file")        // MATCH /Unhandled error in call to function os.Remove started in a goroutine/
+
+	// (io.Writer).Write: interface method calls
+	w.Write(nil)
+	f.Write(nil) // MATCH /Unhandled error in call to function os.File.Write/
+
+	// (*bytes.Buffer).Write*: methods, including promoted ones
+	var buf bytes.Buffer
+	buf.WriteString("")
+	_, _ = buf.WriteByte('a'), buf.WriteRune('a')
+	embedded := struct{ *bytes.Buffer }{&buf}
+	embedded.Write(nil)
+	buf.ReadByte() // MATCH /Unhandled error in call to function bytes.Buffer.ReadByte/
+
+	// fmt.Fprint*(*strings.Builder): only calls writing to a builder, whatever the import name
+	var sb str.Builder
+	fmt.Fprint(&sb, "")
+	fmt.Fprintln(&sb, "")
+	fmt.Fprintf(&buf, "")
+	fmt.Fprintln(&buf, "") // MATCH /Unhandled error in call to function fmt.Fprintln/
+	fmt.Fprint(w, "")      // MATCH /Unhandled error in call to function fmt.Fprint/
+
+	// fixtures.unhandledErrorOptionsHelper: package functions called by identifier
+	unhandledErrorOptionsHelper()
+
+	return n, err
+}
+
+func unhandledErrorOptionsHelper() error {
+	return nil
+}